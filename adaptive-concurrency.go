@@ -0,0 +1,76 @@
+package filemaker
+
+import (
+	"sync"
+	"time"
+)
+
+// AdaptiveConcurrency tunes how many requests a bulk operation runs at a
+// time, AIMD style: the limit starts at min and grows by about one per round
+// of requests while their latency stays within twice the fastest one seen,
+// and is halved on a failed request or a latency above that. It never leaves
+// [min, max]. A controller can be shared by several operations.
+type AdaptiveConcurrency struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	min      float64
+	max      float64
+	limit    float64
+	inFlight int
+	fastest  time.Duration
+}
+
+func NewAdaptiveConcurrency(min, max int) *AdaptiveConcurrency {
+	if min < 1 {
+		min = 1
+	}
+	if max < min {
+		max = min
+	}
+	ac := &AdaptiveConcurrency{
+		min:   float64(min),
+		max:   float64(max),
+		limit: float64(min),
+	}
+	ac.cond = sync.NewCond(&ac.mu)
+	return ac
+}
+
+// Limit returns the current concurrency limit.
+func (ac *AdaptiveConcurrency) Limit() int {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	return int(ac.limit)
+}
+
+// acquire blocks until a request may start.
+func (ac *AdaptiveConcurrency) acquire() {
+	ac.mu.Lock()
+	for ac.inFlight >= int(ac.limit) {
+		ac.cond.Wait()
+	}
+	ac.inFlight++
+	ac.mu.Unlock()
+}
+
+// release records the outcome of a request started with acquire.
+func (ac *AdaptiveConcurrency) release(latency time.Duration, failed bool) {
+	ac.mu.Lock()
+	ac.inFlight--
+	if !failed && (ac.fastest == 0 || latency < ac.fastest) {
+		ac.fastest = latency
+	}
+	if failed || latency > 2*ac.fastest {
+		ac.limit /= 2
+		if ac.limit < ac.min {
+			ac.limit = ac.min
+		}
+	} else {
+		ac.limit += 1 / ac.limit
+		if ac.limit > ac.max {
+			ac.limit = ac.max
+		}
+	}
+	ac.cond.Broadcast()
+	ac.mu.Unlock()
+}
@@ -0,0 +1,54 @@
+package filemaker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdaptiveConcurrency(t *testing.T) {
+	run := func(ac *AdaptiveConcurrency, latency time.Duration, failed bool, times int) {
+		for i := 0; i < times; i++ {
+			ac.acquire()
+			ac.release(latency, failed)
+		}
+	}
+
+	t.Run("Test increase while latency is stable", func(t *testing.T) {
+		ac := NewAdaptiveConcurrency(1, 4)
+		run(ac, 10*time.Millisecond, false, 20)
+		if ac.Limit() != 4 {
+			t.Errorf("Limit() was incorrect, got: %d, want: %d", ac.Limit(), 4)
+		}
+	})
+
+	t.Run("Test back off on failure", func(t *testing.T) {
+		ac := NewAdaptiveConcurrency(1, 8)
+		run(ac, 10*time.Millisecond, false, 100)
+		run(ac, 10*time.Millisecond, true, 1)
+		if ac.Limit() != 4 {
+			t.Errorf("Limit() was incorrect, got: %d, want: %d", ac.Limit(), 4)
+		}
+		run(ac, 10*time.Millisecond, true, 10)
+		if ac.Limit() != 1 {
+			t.Errorf("Limit() was incorrect, got: %d, want: %d", ac.Limit(), 1)
+		}
+	})
+
+	t.Run("Test back off on slow requests", func(t *testing.T) {
+		ac := NewAdaptiveConcurrency(2, 8)
+		run(ac, 10*time.Millisecond, false, 100)
+		run(ac, 50*time.Millisecond, false, 1)
+		if ac.Limit() != 4 {
+			t.Errorf("Limit() was incorrect, got: %d, want: %d", ac.Limit(), 4)
+		}
+	})
+
+	t.Run("Test fixed concurrency", func(t *testing.T) {
+		ac := NewAdaptiveConcurrency(3, 3)
+		run(ac, 10*time.Millisecond, true, 5)
+		run(ac, 10*time.Millisecond, false, 5)
+		if ac.Limit() != 3 {
+			t.Errorf("Limit() was incorrect, got: %d, want: %d", ac.Limit(), 3)
+		}
+	})
+}
@@ -1,13 +1,14 @@
 package filemaker
 
 import (
-	"fmt"
+	"context"
 	"net/http"
+	"time"
 )
 
-const (
-	sessionAuthPath string = "fmi/data/%s/databases/%s/sessions"
-)
+// disconnectTimeout bounds how long Disconnect waits for the server to close
+// a session. It is independent from the operation that used the session.
+var disconnectTimeout = 5 * time.Second
 
 type ConnectionDatasource struct {
 	FmDataSource []FmDatasource `json:"fmDataSource"`
@@ -18,9 +19,24 @@ type FmDatasource struct {
 	Password string `json:"password"`
 }
 
+// Connect creates a session on database. When a Claris ID token is set with
+// SetClarisIDToken, as FileMaker Cloud requires, it is sent instead of the
+// username and password. When a TokenSource is set with SetTokenSource, its
+// token is returned and no session is created.
 func (c *Client) Connect(database string) (*ResponseData, error) {
+	return c.connect(context.Background(), database)
+}
+
+func (c *Client) connect(ctx context.Context, database string) (*ResponseData, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if c.tokenSource != nil {
+		return sessionFromSource(ctx, c.tokenSource)
+	}
+
 	c.mu.RLock()
-	path := fmt.Sprintf(sessionAuthPath, c.version, database)
+	path := c.dataPath("databases", database, "sessions")
 
 	options := &performRequestOptions{
 		Method:      http.MethodPost,
@@ -29,15 +45,19 @@ func (c *Client) Connect(database string) (*ResponseData, error) {
 		ContentType: "application/json",
 		basicAuth:   true,
 	}
+	if c.clarisIDToken != "" {
+		options.basicAuth = false
+		options.Headers = http.Header{"Authorization": []string{"FMID " + c.clarisIDToken}}
+	}
 
-	response, err := c.executeQuery(options)
+	response, err := c.executeQuery(ctx, options)
 	c.mu.RUnlock()
 	return response, err
 }
 
 func (c *Client) ConnectWithDatasource(database string) (*ResponseData, error) {
 	c.mu.RLock()
-	path := fmt.Sprintf(sessionAuthPath, c.version, database)
+	path := c.dataPath("databases", database, "sessions")
 	datasource := FmDatasource{
 		Database: database,
 		Username: c.username,
@@ -54,20 +74,37 @@ func (c *Client) ConnectWithDatasource(database string) (*ResponseData, error) {
 		Body:      fileMakerConnection,
 		basicAuth: true,
 	}
-	response, err := c.executeQuery(options)
+	response, err := c.executeQuery(context.Background(), options)
 	c.mu.RUnlock()
 	return response, err
 }
 
+// Disconnect closes the session identified by token. It always runs with its
+// own short-lived context, so a session is still released when the operation
+// that used it was canceled or timed out. Sessions of a TokenSource are left
+// open for their owner to close.
 func (c *Client) Disconnect(database, token string) (*ResponseData, error) {
+	return c.disconnect(context.Background(), database, token)
+}
+
+// disconnect closes the session under the request id of ctx, but not its
+// deadline or cancellation.
+func (c *Client) disconnect(ctx context.Context, database, token string) (*ResponseData, error) {
+	if c.tokenSource != nil {
+		return &ResponseData{Messages: []Message{{Code: "0", Message: "OK"}}}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(detachRequestID(ctx), disconnectTimeout)
+	defer cancel()
+
 	c.mu.RLock()
-	path := fmt.Sprintf(sessionAuthPath+"/%s", c.version, database, token)
+	path := c.dataPath("databases", database, "sessions", token)
 
 	options := &performRequestOptions{
 		Method: http.MethodDelete,
 		Path:   path,
 	}
-	response, err := c.executeQuery(options)
+	response, err := c.executeQuery(ctx, options)
 	c.mu.RUnlock()
 	return response, err
 }
@@ -0,0 +1,87 @@
+package filemaker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_Disconnect(t *testing.T) {
+	t.Run("Disconnect after canceled operation", func(t *testing.T) {
+		var queries int
+		server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+			queries++
+		})
+		client := newTestClient(t, server.URL)
+
+		responseAuth, err := client.Connect("test")
+		if err != nil {
+			t.Fatalf("Connect() error = %v", err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = client.executeQuery(ctx, &performRequestOptions{
+			Method: http.MethodGet,
+			Path:   "fmi/data/vLatest/databases/test/layouts/test_layout/records",
+		})
+		if !errors.Is(err, context.Canceled) || queries != 0 {
+			t.Fatalf("executeQuery() was incorrect, got: %v after %d requests, want: %v before any request", err, queries, context.Canceled)
+		}
+
+		if _, err := client.Disconnect("test", responseAuth.Response.Token); err != nil {
+			t.Errorf("Disconnect() error = %v", err)
+		}
+		if server.disconnectCount() != 1 {
+			t.Errorf("Disconnect calls was incorrect, got: %d, want: %d", server.disconnectCount(), 1)
+		}
+	})
+
+	t.Run("Disconnect does not hang on a stuck server", func(t *testing.T) {
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer server.Close()
+		defer close(release)
+
+		previous := disconnectTimeout
+		disconnectTimeout = 50 * time.Millisecond
+		defer func() { disconnectTimeout = previous }()
+
+		client := newTestClient(t, server.URL)
+		start := time.Now()
+		if _, err := client.Disconnect("test", "token"); err == nil {
+			t.Errorf("Disconnect() on a stuck server should fail")
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Disconnect took too long, got: %s", elapsed)
+		}
+	})
+}
+
+func TestClient_ConnectWithClarisID(t *testing.T) {
+	var authorization string
+	var hasBasicAuth bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		_, _, hasBasicAuth = r.BasicAuth()
+		w.Write([]byte(`{"response":{"token":"cloud-token"},"messages":[{"code":"0","message":"OK"}]}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, SetClarisIDToken("id-token"))
+	resp, err := client.Connect("test")
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	if authorization != "FMID id-token" || hasBasicAuth {
+		t.Errorf("Authorization was incorrect, got: %s, want: %s", authorization, "FMID id-token")
+	}
+	if resp.Response.Token != "cloud-token" {
+		t.Errorf("Token was incorrect, got: %s, want: %s", resp.Response.Token, "cloud-token")
+	}
+}
@@ -4,22 +4,30 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"log"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
 	"runtime"
 	"strings"
 	"sync"
+	"time"
 )
 
 type Doer interface {
 	Do(*http.Request) (*http.Response, error)
 }
 
+// RequestDumper receives every outgoing request with its fully serialized
+// body, just before it is sent.
+type RequestDumper func(method, url string, body []byte)
+
 type performRequestOptions struct {
 	Method      string
 	Path        string
@@ -28,6 +36,9 @@ type performRequestOptions struct {
 	ContentType string
 	Headers     http.Header
 	basicAuth   bool
+	// excludeFields overrides the client's response field blocklist when
+	// not nil.
+	excludeFields []string
 }
 
 type Client struct {
@@ -36,7 +47,31 @@ type Client struct {
 	username   string
 	password   string
 	version    string //Default vLatest
+	basePath   string //Default fmi/data
 	httpClient *http.Client
+
+	clarisIDToken    string //FileMaker Cloud, replaces username and password
+	maxResponseBytes int64  //0 means unlimited
+	requestDumper    RequestDumper
+	strictScripts    bool
+	readGroup        *callGroup //nil unless request coalescing is enabled
+	clientTrace      *httptrace.ClientTrace
+	doer             Doer   //nil means httpClient
+	requestIDHeader  string //empty means no request id is sent
+	rateLimiter      *rateLimiter
+	acceptLanguage   string //empty means the server default
+	// responseFieldBlocklist names the fields removed from every decoded
+	// record.
+	responseFieldBlocklist []string
+	logger                 *log.Logger
+	slowRequestThreshold   time.Duration
+
+	minTLSVersion      uint16
+	pinnedCertificates []*x509.Certificate
+	insecureSkipVerify bool
+	ownedTransport     *http.Transport //the copy made by configureTLS, nil if none
+
+	tokenSource TokenSource //nil means a session per call
 }
 
 func NewClient(options ...ClientOptions) (*Client, error) {
@@ -49,53 +84,142 @@ func NewClient(options ...ClientOptions) (*Client, error) {
 	if c.version == "" {
 		c.version = DefaultVersion
 	}
+	if c.basePath == "" {
+		c.basePath = DefaultBasePath
+	}
 	if c.httpClient == nil {
 		c.httpClient = http.DefaultClient
 	}
+	if err := c.configureTLS(); err != nil {
+		return nil, err
+	}
 
 	return c, nil
 }
 
-func (c *Client) executeQuery(options *performRequestOptions) (*ResponseData, error) {
-	response, err := c.performRequest(context.Background(), options)
+// Close releases the idle connections of the transport the client copied to
+// apply its TLS options. An http client given to SetHttpClient, the default
+// one or a Doer is left untouched, since others may share it. Sessions are
+// closed by each call, so none is left open. It is safe to call Close more
+// than once.
+func (c *Client) Close() error {
+	if c.ownedTransport != nil {
+		c.ownedTransport.CloseIdleConnections()
+	}
+	return nil
+}
 
-	if response == nil && err != nil {
-		fmt.Errorf("Fail performRequest %s", err.Error())
+func (c *Client) executeQuery(ctx context.Context, options *performRequestOptions) (*ResponseData, error) {
+	response, err := c.performRequest(ctx, options)
+	if response == nil {
+		if err == nil {
+			err = errors.New("filemaker: no response")
+		}
 		return nil, err
 	}
-	var searchResponseData *ResponseData
-	if response != nil && err != nil {
-		defer response.Body.Close()
+	defer response.Body.Close()
 
-		data, err := ioutil.ReadAll(response.Body)
-		if err != nil {
-			fmt.Errorf("Error to get response body : %s", err.Error())
-			return nil, err
+	data, err := c.readBody(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Numbers are kept as json.Number so large numeric field values don't
+	// lose precision in a float64.
+	var searchResponseData *ResponseData
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	err = decoder.Decode(&searchResponseData)
+	if err != nil {
+		contentType := response.Header.Get("Content-Type")
+		if !strings.Contains(contentType, "json") {
+			return nil, newHTTPError(response, contentType, data)
 		}
+		return searchResponseData, err
+	}
+	if searchResponseData == nil {
+		return nil, errors.New("filemaker: empty response body")
+	}
 
-		err = json.Unmarshal(data, &searchResponseData)
-		if err != nil {
-			fmt.Errorf("Error to Unmarshal QueryResponse %s", err.Error())
+	excludeFields := options.excludeFields
+	if excludeFields == nil {
+		excludeFields = c.responseFieldBlocklist
+	}
+	searchResponseData.excludeFields(excludeFields)
+
+	if c.strictScripts {
+		if err := searchResponseData.Response.scriptErr(); err != nil {
 			return searchResponseData, err
 		}
+	}
+
+	return searchResponseData, nil
+}
 
+// dataPath builds the path of a Data API endpoint from the base path, the
+// API version and segments. Each segment is path-escaped, so database, layout
+// or script names containing "/", spaces or "?" stay a single segment.
+func (c *Client) dataPath(segments ...string) string {
+	parts := make([]string, 0, len(segments)+2)
+	parts = append(parts, c.basePath, c.version)
+	for _, segment := range segments {
+		parts = append(parts, url.PathEscape(segment))
 	}
-	if err == nil {
-		defer response.Body.Close()
+	return strings.Join(parts, "/")
+}
 
-		data, err := ioutil.ReadAll(response.Body)
-		if err != nil {
-			fmt.Errorf("Error to get response body : %s", err.Error())
-			return nil, err
-		}
-		err = json.Unmarshal(data, &searchResponseData)
+// maxErrorBodySnippet is the number of body bytes kept in an HTTPError.
+const maxErrorBodySnippet = 256
+
+func newHTTPError(response *http.Response, contentType string, body []byte) *HTTPError {
+	snippet := strings.TrimSpace(string(body))
+	if len(snippet) > maxErrorBodySnippet {
+		snippet = snippet[:maxErrorBodySnippet] + "..."
+	}
+	return &HTTPError{
+		StatusCode:  response.StatusCode,
+		Status:      response.Status,
+		ContentType: contentType,
+		Body:        snippet,
+	}
+}
+
+// readBody reads the whole response body, failing with a ResponseTooLargeError
+// when it exceeds the configured maximum response size.
+func (c *Client) readBody(body io.Reader) ([]byte, error) {
+	if c.maxResponseBytes <= 0 {
+		data, err := ioutil.ReadAll(body)
 		if err != nil {
-			fmt.Errorf("Error to Unmarshal QueryResponse %s", err.Error())
-			return searchResponseData, err
+			return nil, fmt.Errorf("filemaker: couldn't read response body: %w", err)
 		}
+		return data, nil
 	}
 
-	return searchResponseData, nil
+	data, err := ioutil.ReadAll(io.LimitReader(body, c.maxResponseBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("filemaker: couldn't read response body: %w", err)
+	}
+	if int64(len(data)) > c.maxResponseBytes {
+		return nil, &ResponseTooLargeError{Limit: c.maxResponseBytes}
+	}
+	return data, nil
+}
+
+// Ping checks that the server is reachable and the Data API responds, by
+// reading the product info. It is meant for readiness probes: it sends one
+// request, never retries and stops when ctx is done. The product info doesn't
+// need a session, so Ping doesn't validate the credentials. A network failure
+// is returned as the transport error, an unexpected response as an
+// *HTTPError and a Data API error as a *FileMakerError.
+func (c *Client) Ping(ctx context.Context) error {
+	resp, err := c.executeQuery(ctx, &performRequestOptions{
+		Method: http.MethodGet,
+		Path:   c.dataPath("productInfo"),
+	})
+	if err != nil {
+		return err
+	}
+	return resp.Err()
 }
 
 func (c *Client) performRequest(ctx context.Context, opt *performRequestOptions) (*http.Response, error) {
@@ -115,6 +239,9 @@ func (c *Client) performRequest(ctx context.Context, opt *performRequestOptions)
 	completeUrl := fmt.Sprintf("%s/%s", c.url, pathWithParams)
 
 	req, err := c.NewRequest(opt.Method, completeUrl)
+	if err != nil {
+		return nil, err
+	}
 	if opt.ContentType != "" {
 		req.Header.Set("Content-Type", opt.ContentType)
 	}
@@ -137,7 +264,37 @@ func (c *Client) performRequest(ctx context.Context, opt *performRequestOptions)
 		req.setBasicAuth(c.username, c.password)
 	}
 
+	if c.acceptLanguage != "" {
+		req.Header.Set("Accept-Language", c.acceptLanguage)
+	}
+
+	if c.requestIDHeader != "" && req.Header.Get(c.requestIDHeader) == "" {
+		if id := requestID(ctx); id != "" {
+			req.Header.Set(c.requestIDHeader, id)
+		}
+	}
+
+	if c.requestDumper != nil {
+		if err := req.dump(c.requestDumper); err != nil {
+			return nil, fmt.Errorf("filemaker: couldn't dump request: %v", err)
+		}
+	}
+
+	if c.clientTrace != nil {
+		ctx = httptrace.WithClientTrace(ctx, c.clientTrace)
+	}
+
+	if c.rateLimiter != nil {
+		if err := c.rateLimiter.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	start := time.Now()
 	resp, err := c.Do((*http.Request)(req).WithContext(ctx))
+	if elapsed := time.Since(start); c.logger != nil && c.slowRequestThreshold > 0 && elapsed > c.slowRequestThreshold {
+		c.logger.Printf("filemaker: slow request %s %s took %v", opt.Method, opt.Path, elapsed)
+	}
 	return resp, err
 
 }
@@ -235,10 +392,28 @@ func (r *Request) setBodyReader(body io.Reader) error {
 	return nil
 }
 
+func (r *Request) dump(dumper RequestDumper) error {
+	var body []byte
+	if r.Body != nil {
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return err
+		}
+		r.Body.Close()
+		body = data
+		r.setBodyReader(bytes.NewReader(body))
+	}
+	dumper(r.Method, r.URL.String(), body)
+	return nil
+}
+
 func (r *Request) setBasicAuth(username, password string) {
 	((*http.Request)(r)).SetBasicAuth(username, password)
 }
 
 func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if c.doer != nil {
+		return c.doer.Do(req)
+	}
 	return c.httpClient.Do(req)
 }
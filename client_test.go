@@ -0,0 +1,434 @@
+package filemaker
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeServer emulates the FileMaker session endpoints and delegates every
+// other request to handler.
+type fakeServer struct {
+	*httptest.Server
+	mu          sync.Mutex
+	disconnects []string
+}
+
+func newFakeServer(t *testing.T, handler http.HandlerFunc) *fakeServer {
+	t.Helper()
+	fs := &fakeServer{}
+	fs.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/sessions") {
+			switch r.Method {
+			case http.MethodPost:
+				w.Write([]byte(`{"response":{"token":"test-token"},"messages":[{"code":"0","message":"OK"}]}`))
+				return
+			case http.MethodDelete:
+				fs.mu.Lock()
+				fs.disconnects = append(fs.disconnects, r.URL.Path)
+				fs.mu.Unlock()
+				w.Write([]byte(`{"response":{},"messages":[{"code":"0","message":"OK"}]}`))
+				return
+			}
+		}
+		if handler == nil {
+			w.Write([]byte(`{"response":{},"messages":[{"code":"0","message":"OK"}]}`))
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(fs.Close)
+	return fs
+}
+
+func (fs *fakeServer) disconnectCount() int {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return len(fs.disconnects)
+}
+
+func newTestClient(t *testing.T, url string, options ...ClientOptions) *Client {
+	t.Helper()
+	options = append([]ClientOptions{
+		SetURL(url),
+		SetUsername("user"),
+		SetPassword("pass"),
+	}, options...)
+	client, err := NewClient(options...)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	return client
+}
+
+func TestClient_MaxResponseBytes(t *testing.T) {
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"response":{"data":[{"fieldData":{"name":"` + strings.Repeat("x", 512) + `"}}]},"messages":[{"code":"0","message":"OK"}]}`))
+	})
+
+	t.Run("Response over the limit", func(t *testing.T) {
+		client := newTestClient(t, server.URL, SetMaxResponseBytes(256))
+		_, err := NewRecordService("test", "test_layout", client).GetById("1")
+		if _, ok := err.(*ResponseTooLargeError); !ok {
+			t.Errorf("Error was incorrect, got: %v, want: %T", err, &ResponseTooLargeError{})
+		}
+	})
+
+	t.Run("Response under the limit", func(t *testing.T) {
+		client := newTestClient(t, server.URL, SetMaxResponseBytes(4096))
+		if _, err := NewRecordService("test", "test_layout", client).GetById("1"); err != nil {
+			t.Errorf("GetById() error = %v", err)
+		}
+	})
+}
+
+func TestClient_RequestDumper(t *testing.T) {
+	server := newFakeServer(t, nil)
+	var dumped []string
+	client := newTestClient(t, server.URL, SetRequestDumper(func(method, url string, body []byte) {
+		dumped = append(dumped, method+" "+url+" "+string(body))
+	}))
+
+	_, err := NewSearchService("test", "test_layout", client).
+		GroupQueries(NewGroupQuery(NewQueryFieldOperator("name", "pablo", Equal))).
+		Do()
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	want := "POST " + server.URL + "/fmi/data/vLatest/databases/test/layouts/test_layout/_find {\"query\":[{\"name\":\"==pablo\"}]}"
+	if len(dumped) != 3 || dumped[1] != want {
+		t.Errorf("Dumped requests were incorrect, got: %v, want: %s", dumped, want)
+	}
+}
+
+func TestClient_dataPath(t *testing.T) {
+	t.Run("Test default base path", func(t *testing.T) {
+		client := newTestClient(t, "https://localhost")
+		got := client.dataPath("databases", "test", "layouts", "test_layout", "records", "1")
+		want := "fmi/data/vLatest/databases/test/layouts/test_layout/records/1"
+		if got != want {
+			t.Errorf("Path was incorrect, got: %s, want: %s", got, want)
+		}
+	})
+
+	t.Run("Test custom base path", func(t *testing.T) {
+		client := newTestClient(t, "https://localhost", SetBasePath("/proxy/fmi/data/"), SetVersion("v1"))
+		got := client.dataPath("databases", "test", "sessions")
+		want := "proxy/fmi/data/v1/databases/test/sessions"
+		if got != want {
+			t.Errorf("Path was incorrect, got: %s, want: %s", got, want)
+		}
+	})
+}
+
+func TestClient_HTMLErrorPage(t *testing.T) {
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("<html><body>Bad Gateway (proxy)</body></html>"))
+	})
+	client := newTestClient(t, server.URL)
+
+	_, err := NewRecordService("test", "test_layout", client).GetById("1")
+	httpErr, ok := err.(*HTTPError)
+	if !ok {
+		t.Fatalf("Error was incorrect, got: %v, want: %T", err, &HTTPError{})
+	}
+	if httpErr.StatusCode != http.StatusBadGateway || !strings.Contains(httpErr.Body, "Bad Gateway (proxy)") {
+		t.Errorf("HTTPError was incorrect, got: %+v", httpErr)
+	}
+}
+
+func TestClient_PathEscaping(t *testing.T) {
+	var paths []string
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.EscapedPath())
+		w.Write([]byte(`{"response":{},"messages":[{"code":"0","message":"OK"}]}`))
+	})
+	client := newTestClient(t, server.URL)
+
+	if _, err := NewRecordService("Sales Data", "Orders/Detail", client).GetById("1"); err != nil {
+		t.Fatalf("GetById() error = %v", err)
+	}
+	if _, err := NewScriptService("Sales Data", "Orders/Detail", client).Execute("Close?", ""); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	want := []string{
+		"/fmi/data/vLatest/databases/Sales%20Data/layouts/Orders%2FDetail/records/1",
+		"/fmi/data/vLatest/databases/Sales%20Data/layouts/Orders%2FDetail/script/Close%3F",
+	}
+	if strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Errorf("Paths were incorrect, got: %v, want: %v", paths, want)
+	}
+}
+
+func TestClient_ClientTrace(t *testing.T) {
+	server := newFakeServer(t, nil)
+	var mu sync.Mutex
+	var reused, created int
+	client := newTestClient(t, server.URL, SetClientTrace(&httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			mu.Lock()
+			defer mu.Unlock()
+			if info.Reused {
+				reused++
+			} else {
+				created++
+			}
+		},
+	}))
+
+	if _, err := NewRecordService("test", "test_layout", client).GetById("1"); err != nil {
+		t.Fatalf("GetById() error = %v", err)
+	}
+	if created != 1 || reused != 2 {
+		t.Errorf("Connections were incorrect, got: %d new and %d reused, want: %d new and %d reused", created, reused, 1, 2)
+	}
+}
+
+func TestClient_Ping(t *testing.T) {
+	t.Run("Test reachable server", func(t *testing.T) {
+		var path string
+		server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+			w.Write([]byte(`{"response":{"productInfo":{"name":"FileMaker Data API Engine"}},"messages":[{"code":"0","message":"OK"}]}`))
+		})
+		if err := newTestClient(t, server.URL).Ping(context.Background()); err != nil {
+			t.Errorf("Ping() error = %v", err)
+		}
+		if path != "/fmi/data/vLatest/productInfo" {
+			t.Errorf("Path was incorrect, got: %s, want: %s", path, "/fmi/data/vLatest/productInfo")
+		}
+	})
+
+	t.Run("Test unreachable server", func(t *testing.T) {
+		server := newFakeServer(t, nil)
+		url := server.URL
+		server.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if err := newTestClient(t, url).Ping(ctx); err == nil {
+			t.Errorf("Ping() of a closed server should fail")
+		}
+	})
+
+	t.Run("Test proxy error page", func(t *testing.T) {
+		server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("<html>Service Unavailable</html>"))
+		})
+		if _, ok := newTestClient(t, server.URL).Ping(context.Background()).(*HTTPError); !ok {
+			t.Errorf("Ping() error type was incorrect, want: %T", &HTTPError{})
+		}
+	})
+}
+
+type doerFunc func(*http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestClient_SetDoer(t *testing.T) {
+	t.Run("Test custom doer", func(t *testing.T) {
+		var path string
+		doer := doerFunc(func(req *http.Request) (*http.Response, error) {
+			path = req.URL.Path
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       ioutil.NopCloser(strings.NewReader(`{"response":{},"messages":[{"code":"0","message":"OK"}]}`)),
+			}, nil
+		})
+		client, err := NewClient(SetURL("http://filemaker.invalid"), SetDoer(doer))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := client.Ping(context.Background()); err != nil {
+			t.Errorf("Ping() error = %v", err)
+		}
+		if path != "/fmi/data/vLatest/productInfo" {
+			t.Errorf("Path was incorrect, got: %s, want: %s", path, "/fmi/data/vLatest/productInfo")
+		}
+	})
+
+	t.Run("Test doer without response", func(t *testing.T) {
+		doer := doerFunc(func(req *http.Request) (*http.Response, error) {
+			return nil, nil
+		})
+		client, err := NewClient(SetURL("http://filemaker.invalid"), SetDoer(doer))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := client.Ping(context.Background()); err == nil {
+			t.Errorf("Ping() without a response should fail")
+		}
+	})
+
+	t.Run("Test null body", func(t *testing.T) {
+		doer := doerFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       ioutil.NopCloser(strings.NewReader(`null`)),
+			}, nil
+		})
+		client, err := NewClient(SetURL("http://filemaker.invalid"), SetDoer(doer), SetResponseFieldBlocklist([]string{"notes"}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := client.Ping(context.Background()); err == nil {
+			t.Errorf("Ping() of a null body should fail")
+		}
+	})
+
+	t.Run("Test nil doer", func(t *testing.T) {
+		if _, err := NewClient(SetDoer(nil)); err == nil {
+			t.Errorf("NewClient() with a nil Doer should fail")
+		}
+	})
+}
+
+func TestClient_RequestIDHeader(t *testing.T) {
+	var mu sync.Mutex
+	var ids []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		ids = append(ids, r.Header.Get("X-Request-ID"))
+		mu.Unlock()
+		w.Write([]byte(`{"response":{},"messages":[{"code":"0","message":"OK"}]}`))
+	}))
+	t.Cleanup(server.Close)
+	client := newTestClient(t, server.URL, SetRequestIDHeader("X-Request-ID"))
+
+	t.Run("Test generated ids", func(t *testing.T) {
+		ids = nil
+		client.Ping(context.Background())
+		client.Ping(context.Background())
+		if len(ids) != 2 || ids[0] == "" || ids[0] == ids[1] {
+			t.Errorf("Request ids were incorrect, got: %v, want two distinct ids", ids)
+		}
+	})
+
+	t.Run("Test id from context", func(t *testing.T) {
+		ids = nil
+		client.Ping(ContextWithRequestID(context.Background(), "abc-123"))
+		if len(ids) != 1 || ids[0] != "abc-123" {
+			t.Errorf("Request id was incorrect, got: %v, want: %s", ids, "abc-123")
+		}
+	})
+
+	t.Run("Test one id per operation", func(t *testing.T) {
+		ids = nil
+		NewRecordService("test", "layout", client).Create(&Payload{FieldData: map[string]interface{}{"name": "a"}})
+		if len(ids) != 3 || ids[0] == "" || ids[1] != ids[0] || ids[2] != ids[0] {
+			t.Errorf("Request ids were incorrect, got: %v, want the same id for session, create and logout", ids)
+		}
+	})
+
+	t.Run("Test disabled by default", func(t *testing.T) {
+		ids = nil
+		newTestClient(t, server.URL).Ping(context.Background())
+		if len(ids) != 1 || ids[0] != "" {
+			t.Errorf("Request id was incorrect, got: %v, want none", ids)
+		}
+	})
+}
+
+func TestClient_AcceptLanguage(t *testing.T) {
+	var languages []string
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		languages = append(languages, r.Header.Get("Accept-Language"))
+		w.Write([]byte(`{"response":{},"messages":[{"code":"0","message":"OK"}]}`))
+	})
+
+	newTestClient(t, server.URL, SetAcceptLanguage("es")).Ping(context.Background())
+	newTestClient(t, server.URL).Ping(context.Background())
+	if len(languages) != 2 || languages[0] != "es" || languages[1] != "" {
+		t.Errorf("Accept-Language was incorrect, got: %q, want: %q", languages, []string{"es", ""})
+	}
+}
+
+func TestClient_SlowRequestThreshold(t *testing.T) {
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte(`{"response":{},"messages":[{"code":"0","message":"OK"}]}`))
+	})
+
+	t.Run("Test slow request logged", func(t *testing.T) {
+		var buf bytes.Buffer
+		client := newTestClient(t, server.URL, SetLogger(log.New(&buf, "", 0)), SetSlowRequestThreshold(5*time.Millisecond))
+		client.Ping(context.Background())
+		if !strings.HasPrefix(buf.String(), "filemaker: slow request GET fmi/data/vLatest/productInfo took ") {
+			t.Errorf("Log was incorrect, got: %q", buf.String())
+		}
+	})
+
+	t.Run("Test fast request not logged", func(t *testing.T) {
+		var buf bytes.Buffer
+		client := newTestClient(t, server.URL, SetLogger(log.New(&buf, "", 0)), SetSlowRequestThreshold(time.Minute))
+		client.Ping(context.Background())
+		if buf.Len() != 0 {
+			t.Errorf("Log was incorrect, got: %q, want none", buf.String())
+		}
+	})
+}
+
+func TestClient_Close(t *testing.T) {
+	server := newFakeServer(t, nil)
+	var mu sync.Mutex
+	var reused []bool
+	trace := &httptrace.ClientTrace{GotConn: func(info httptrace.GotConnInfo) {
+		mu.Lock()
+		reused = append(reused, info.Reused)
+		mu.Unlock()
+	}}
+	pings := func(client *Client) []bool {
+		mu.Lock()
+		reused = nil
+		mu.Unlock()
+		client.Ping(context.Background())
+		client.Ping(context.Background())
+		if err := client.Close(); err != nil {
+			t.Errorf("Close() error = %v", err)
+		}
+		if err := client.Close(); err != nil {
+			t.Errorf("Second Close() error = %v", err)
+		}
+		client.Ping(context.Background())
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]bool(nil), reused...)
+	}
+
+	t.Run("Test owned transport", func(t *testing.T) {
+		client := newTestClient(t, server.URL,
+			SetHttpClient(&http.Client{Transport: &http.Transport{}}),
+			SetMinTLSVersion(tls.VersionTLS12),
+			SetClientTrace(trace))
+		if got := pings(client); len(got) != 3 || !got[1] || got[2] {
+			t.Errorf("Connection reuse was incorrect, got: %v, want: %v", got, []bool{false, true, false})
+		}
+	})
+
+	t.Run("Test shared http client is left untouched", func(t *testing.T) {
+		client := newTestClient(t, server.URL,
+			SetHttpClient(&http.Client{Transport: &http.Transport{}}),
+			SetClientTrace(trace))
+		if got := pings(client); len(got) != 3 || !got[1] || !got[2] {
+			t.Errorf("Connection reuse was incorrect, got: %v, want: %v", got, []bool{false, true, true})
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package filemaker
+
+import (
+	"sync"
+)
+
+// coalescedCall is an in-flight read shared by every caller with its key.
+type coalescedCall struct {
+	wg   sync.WaitGroup
+	resp *ResponseData
+	err  error
+}
+
+// callGroup deduplicates concurrent identical reads: while a call for a key
+// is in flight, other callers with the same key wait for its result instead
+// of sending their own request.
+type callGroup struct {
+	mu    sync.Mutex
+	calls map[string]*coalescedCall
+}
+
+func (g *callGroup) do(key string, fn func() (*ResponseData, error)) (*ResponseData, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*coalescedCall)
+	}
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		call.wg.Wait()
+		return call.resp, call.err
+	}
+	call := &coalescedCall{}
+	call.wg.Add(1)
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	call.resp, call.err = fn()
+	call.wg.Done()
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+
+	return call.resp, call.err
+}
+
+// coalesce runs the read fn, sharing it with concurrent calls for the same
+// key when request coalescing is enabled.
+func (c *Client) coalesce(key string, fn func() (*ResponseData, error)) (*ResponseData, error) {
+	if c.readGroup == nil {
+		return fn()
+	}
+	return c.readGroup.do(key, fn)
+}
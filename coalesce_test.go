@@ -0,0 +1,51 @@
+package filemaker
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_RequestCoalescing(t *testing.T) {
+	var reads, writes int32
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			atomic.AddInt32(&reads, 1)
+			time.Sleep(100 * time.Millisecond)
+		} else {
+			atomic.AddInt32(&writes, 1)
+		}
+		w.Write([]byte(`{"response":{"data":[{"fieldData":{"name":"pablo"},"recordId":"1","modId":"0"}]},"messages":[{"code":"0","message":"OK"}]}`))
+	})
+	service := NewRecordService("test", "test_layout", newTestClient(t, server.URL, EnableRequestCoalescing()))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := service.GetById("1"); err != nil {
+				t.Errorf("GetById() error = %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := service.Edit("1", &Payload{FieldData: map[string]string{"name": "pablo"}}); err != nil {
+				t.Errorf("Edit() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if reads != 1 {
+		t.Errorf("Reads was incorrect, got: %d, want: %d", reads, 1)
+	}
+	if writes != 5 {
+		t.Errorf("Writes was incorrect, got: %d, want: %d", writes, 5)
+	}
+	if server.disconnectCount() != 6 {
+		t.Errorf("Disconnect calls was incorrect, got: %d, want: %d", server.disconnectCount(), 6)
+	}
+}
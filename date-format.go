@@ -0,0 +1,39 @@
+package filemaker
+
+import "time"
+
+// DateFormat is the format the Data API uses for date, time and timestamp
+// values in requests and responses.
+type DateFormat int
+
+const (
+	USDateFormat         DateFormat = 0
+	FileLocaleDateFormat DateFormat = 1
+	ISO8601DateFormat    DateFormat = 2
+)
+
+const (
+	usDateLayout       = "01/02/2006"
+	usTimestampLayout  = "01/02/2006 15:04:05"
+	isoDateLayout      = "2006-01-02"
+	isoTimestampLayout = "2006-01-02T15:04:05"
+)
+
+// FormatDate formats t as a FileMaker date in format. The file locale isn't
+// known client side, so FileLocaleDateFormat formats like USDateFormat.
+func FormatDate(t time.Time, format DateFormat) string {
+	if format == ISO8601DateFormat {
+		return t.Format(isoDateLayout)
+	}
+	return t.Format(usDateLayout)
+}
+
+// FormatTimestamp formats t as a FileMaker timestamp in format. The file
+// locale isn't known client side, so FileLocaleDateFormat formats like
+// USDateFormat.
+func FormatTimestamp(t time.Time, format DateFormat) string {
+	if format == ISO8601DateFormat {
+		return t.Format(isoTimestampLayout)
+	}
+	return t.Format(usTimestampLayout)
+}
@@ -0,0 +1,188 @@
+package filemaker
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	noRecordsCode   = "401"
+	recordInUseCode = "301"
+)
+
+// permissionCodes are the FileMaker errors reported when the privilege set
+// of the account doesn't allow an operation.
+var permissionCodes = map[string]bool{
+	"9":   true, // Insufficient privileges
+	"802": true, // Unable to open the file
+}
+
+// ResponseTooLargeError is returned when a response body exceeds the limit
+// configured with SetMaxResponseBytes.
+type ResponseTooLargeError struct {
+	Limit int64
+}
+
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("filemaker: response body exceeds %d bytes", e.Limit)
+}
+
+var (
+	// ErrNotFound is returned by lookups that matched no record.
+	ErrNotFound = errors.New("filemaker: record not found")
+	// ErrMultipleRecords is returned by lookups expecting a single record
+	// that matched more than one.
+	ErrMultipleRecords = errors.New("filemaker: more than one record found")
+)
+
+// BatchError collects the per-record failures of an operation applied to
+// many records, keyed by record ID.
+type BatchError struct {
+	Errors map[string]error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("filemaker: %d records failed", len(e.Errors))
+}
+
+// ImportError is returned by ImportTransactional when the payload at Index
+// couldn't be created. Rollback holds the records created before it that
+// couldn't be deleted again, keyed by record ID; it is nil when the rollback
+// succeeded.
+type ImportError struct {
+	Index    int
+	Err      error
+	Rollback *BatchError
+}
+
+func (e *ImportError) Error() string {
+	if e.Rollback != nil {
+		return fmt.Sprintf("filemaker: import failed at payload %d: %v (rollback left %d records)", e.Index, e.Err, len(e.Rollback.Errors))
+	}
+	return fmt.Sprintf("filemaker: import failed at payload %d: %v", e.Index, e.Err)
+}
+
+func (e *ImportError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError is returned before any request is sent when the input of
+// an operation is invalid.
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Field == "" {
+		return "filemaker: " + e.Message
+	}
+	return fmt.Sprintf("filemaker: invalid %s: %s", e.Field, e.Message)
+}
+
+// HTTPError is returned when the server answers with a body that isn't the
+// Data API JSON, e.g. an HTML error page from a proxy.
+type HTTPError struct {
+	StatusCode  int
+	Status      string
+	ContentType string
+	Body        string //truncated body snippet
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("filemaker: HTTP %s: %s", e.Status, e.Body)
+}
+
+// TimeoutError is returned when an operation run with its own deadline
+// didn't complete in time.
+type TimeoutError struct {
+	Operation string
+	Timeout   time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("filemaker: %s timed out after %s", e.Operation, e.Timeout)
+}
+
+// FileMakerError is a non-zero error code reported by FileMaker in the
+// messages of a response.
+type FileMakerError struct {
+	Code    string
+	Message string
+	// Messages holds every message of the response, the first one being Code
+	// and Message. Some failures add a more specific message after it.
+	Messages []Message
+}
+
+func (e *FileMakerError) Error() string {
+	return fmt.Sprintf("filemaker: error %s: %s", e.Code, e.Message)
+}
+
+// IsNoRecordsError reports whether err is the FileMaker error 401 returned
+// when a find matches no records.
+func IsNoRecordsError(err error) bool {
+	var fmErr *FileMakerError
+	return errors.As(err, &fmErr) && fmErr.Code == noRecordsCode
+}
+
+// PermissionError is returned by ResponseData.Err for the FileMaker errors
+// caused by the privilege set of the account, e.g. a script-only account
+// running a find. The FileMaker error is available through errors.As.
+type PermissionError struct {
+	Err *FileMakerError
+}
+
+func (e *PermissionError) Error() string {
+	return fmt.Sprintf("filemaker: error %s: %s (check the privilege set of the account)", e.Err.Code, e.Err.Message)
+}
+
+func (e *PermissionError) Unwrap() error {
+	return e.Err
+}
+
+// IsPermissionError reports whether err is a FileMaker error caused by
+// insufficient privileges.
+func IsPermissionError(err error) bool {
+	var permErr *PermissionError
+	return errors.As(err, &permErr)
+}
+
+func newFileMakerError(messages []Message) error {
+	code := messages[0].Code
+	fmErr := &FileMakerError{Code: code, Message: messages[0].Message, Messages: messages}
+	if permissionCodes[code] {
+		return &PermissionError{Err: fmErr}
+	}
+	return fmErr
+}
+
+// IsLockError reports whether err is the FileMaker error 301 returned while
+// the record is in use by another user. The edit can be retried, see
+// recordService.RetryOnLock.
+func IsLockError(err error) bool {
+	var fmErr *FileMakerError
+	return errors.As(err, &fmErr) && fmErr.Code == recordInUseCode
+}
+
+type ScriptStage string
+
+const (
+	PreRequestStage ScriptStage = "prerequest"
+	PreSortStage    ScriptStage = "presort"
+	// MainStage is a script run on its own, see scriptService.Validate.
+	MainStage ScriptStage = "main"
+)
+
+// ScriptStageError is returned, when SetStrictScriptErrors is enabled, by a
+// request whose pre-request or pre-sort script reported a non-zero error. It
+// is also returned by scriptService.Validate.
+type ScriptStageError struct {
+	Stage  ScriptStage
+	Code   string
+	Result string
+}
+
+func (e *ScriptStageError) Error() string {
+	return fmt.Sprintf("filemaker: %s script error %s", e.Stage, e.Code)
+}
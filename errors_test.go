@@ -0,0 +1,112 @@
+package filemaker
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestResponseData_Err(t *testing.T) {
+	t.Run("Test success response", func(t *testing.T) {
+		resp := &ResponseData{Messages: []Message{{Code: "0", Message: "OK"}}}
+		if err := resp.Err(); err != nil {
+			t.Errorf("Err() was incorrect, got: %v, want: nil", err)
+		}
+	})
+
+	t.Run("Test no records response", func(t *testing.T) {
+		resp := &ResponseData{Messages: []Message{{Code: "401", Message: "No records match the request"}}}
+		err := resp.Err()
+		if !IsNoRecordsError(err) {
+			t.Errorf("IsNoRecordsError() was incorrect, got: false, want: true")
+		}
+		if !IsNoRecordsError(fmt.Errorf("wrapped: %w", err)) {
+			t.Errorf("IsNoRecordsError() with wrapped error was incorrect, got: false, want: true")
+		}
+	})
+
+	t.Run("Test other errors", func(t *testing.T) {
+		resp := &ResponseData{Messages: []Message{{Code: "102", Message: "Field is missing"}}}
+		if IsNoRecordsError(resp.Err()) || IsNoRecordsError(errors.New("401")) {
+			t.Errorf("IsNoRecordsError() was incorrect, got: true, want: false")
+		}
+	})
+}
+
+func TestFileMakerError_Messages(t *testing.T) {
+	resp := &ResponseData{Messages: []Message{
+		{Code: "500", Message: "Date value does not meet validation entry options"},
+		{Code: "507", Message: "Value in field failed calculation test of validation entry option"},
+	}}
+	var fmErr *FileMakerError
+	if !errors.As(resp.Err(), &fmErr) {
+		t.Fatalf("Err() error type was incorrect, want: %T", fmErr)
+	}
+	if fmErr.Code != "500" || len(fmErr.Messages) != 2 || fmErr.Messages[1].Code != "507" {
+		t.Errorf("FileMakerError was incorrect, got: %+v", fmErr)
+	}
+	if fmErr.Error() != "filemaker: error 500: Date value does not meet validation entry options" {
+		t.Errorf("Error() was incorrect, got: %s", fmErr.Error())
+	}
+}
+
+func TestIsPermissionError(t *testing.T) {
+	for _, code := range []string{"9", "802"} {
+		t.Run("Test code "+code, func(t *testing.T) {
+			err := (&ResponseData{Messages: []Message{{Code: code, Message: "Insufficient privileges"}}}).Err()
+			if !IsPermissionError(err) {
+				t.Errorf("IsPermissionError() was incorrect, got: false, want: true")
+			}
+			var fmErr *FileMakerError
+			if !errors.As(err, &fmErr) || fmErr.Code != code {
+				t.Errorf("Unwrapped FileMakerError was incorrect, got: %v, want code: %s", fmErr, code)
+			}
+		})
+	}
+
+	t.Run("Test other errors", func(t *testing.T) {
+		err := (&ResponseData{Messages: []Message{{Code: "401", Message: "No records match the request"}}}).Err()
+		if IsPermissionError(err) || IsPermissionError(nil) {
+			t.Errorf("IsPermissionError() was incorrect, got: true, want: false")
+		}
+	})
+}
+
+func TestClient_StrictScriptErrors(t *testing.T) {
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"response":{"scriptError.prerequest":"5","scriptResult.prerequest":"invalid","data":[]},"messages":[{"code":"0","message":"OK"}]}`))
+	})
+
+	t.Run("Test strict mode", func(t *testing.T) {
+		client := newTestClient(t, server.URL, SetStrictScriptErrors(true))
+		resp, err := NewSearchService("test", "test_layout", client).
+			GroupQueries(NewGroupQuery(NewQueryFieldOperator("status", "open", Equal))).
+			SetPreRequestScript("Validate", "").
+			Do()
+		scriptErr, ok := err.(*ScriptStageError)
+		if !ok {
+			t.Fatalf("Error was incorrect, got: %v, want: %T", err, &ScriptStageError{})
+		}
+		if scriptErr.Stage != PreRequestStage || scriptErr.Code != "5" || scriptErr.Result != "invalid" {
+			t.Errorf("ScriptStageError was incorrect, got: %+v", scriptErr)
+		}
+		if resp == nil || resp.Response.ScriptErrorPreRequest != "5" {
+			t.Errorf("Response should be returned with the script error")
+		}
+	})
+
+	t.Run("Test default mode", func(t *testing.T) {
+		client := newTestClient(t, server.URL)
+		resp, err := NewSearchService("test", "test_layout", client).
+			GroupQueries(NewGroupQuery(NewQueryFieldOperator("status", "open", Equal))).
+			SetPreRequestScript("Validate", "").
+			Do()
+		if err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+		if resp.Response.ScriptErrorPreRequest != "5" {
+			t.Errorf("ScriptErrorPreRequest was incorrect, got: %s, want: %s", resp.Response.ScriptErrorPreRequest, "5")
+		}
+	})
+}
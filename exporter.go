@@ -0,0 +1,135 @@
+package filemaker
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+type ExportFormat string
+
+const (
+	// JSONLinesFormat writes one JSON record per line.
+	JSONLinesFormat ExportFormat = "jsonl"
+	// CSVFormat writes the fieldData of each record as a CSV row, with a
+	// header made of the first record's field names in sorted order.
+	CSVFormat ExportFormat = "csv"
+)
+
+const defaultExportPageSize = 100
+
+// Exporter dumps every record of a layout to an io.Writer. Records are
+// fetched one page at a time under a single session, so at most one page is
+// held in memory.
+type Exporter struct {
+	service  *recordService
+	pageSize int
+	sorters  []*Sorter
+}
+
+func NewExporter(service *recordService, pageSize int) *Exporter {
+	if pageSize <= 0 {
+		pageSize = defaultExportPageSize
+	}
+	return &Exporter{
+		service:  service,
+		pageSize: pageSize,
+	}
+}
+
+// Sorters sets the order records are exported in.
+func (e *Exporter) Sorters(sorters ...*Sorter) *Exporter {
+	e.sorters = sorters
+	return e
+}
+
+// Export writes all records of the layout to w in format. It stops between
+// pages when ctx is done.
+func (e *Exporter) Export(ctx context.Context, w io.Writer, format ExportFormat) error {
+	var write func(datum *Datum) error
+	var flush func() error
+	switch format {
+	case JSONLinesFormat:
+		encoder := json.NewEncoder(w)
+		write = func(datum *Datum) error { return encoder.Encode(datum) }
+		flush = func() error { return nil }
+	case CSVFormat:
+		csvWriter := csv.NewWriter(w)
+		var header []string
+		write = func(datum *Datum) error {
+			fieldData, err := fieldDataMap(datum.FieldData)
+			if err != nil {
+				return err
+			}
+			if header == nil {
+				header = sortedKeys(fieldData)
+				if err := csvWriter.Write(header); err != nil {
+					return err
+				}
+			}
+			row := make([]string, len(header))
+			for i, name := range header {
+				if value, ok := fieldData[name]; ok && value != nil {
+					row[i] = fmt.Sprint(value)
+				}
+			}
+			return csvWriter.Write(row)
+		}
+		flush = func() error {
+			csvWriter.Flush()
+			return csvWriter.Error()
+		}
+	default:
+		return &ValidationError{Field: "format", Message: fmt.Sprintf("unsupported export format %q", format)}
+	}
+	if err := validateSorters(e.sorters); err != nil {
+		return err
+	}
+
+	ctx = e.service.client.operationContext(ctx)
+	responseAuth, err := e.service.client.connect(ctx, e.service.database)
+	if err != nil {
+		return err
+	}
+
+	defer e.service.client.disconnect(ctx, e.service.database, responseAuth.Response.Token)
+
+	limit := strconv.Itoa(e.pageSize)
+	for offset := 1; ; offset += e.pageSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		resp, err := e.service.list(ctx, responseAuth.Response.Token, strconv.Itoa(offset), limit, e.sorters...)
+		if err != nil {
+			return err
+		}
+		if err := resp.Err(); err != nil {
+			if IsNoRecordsError(err) {
+				break
+			}
+			return err
+		}
+		for i := range resp.Response.Data {
+			if err := write(&resp.Response.Data[i]); err != nil {
+				return err
+			}
+		}
+		if len(resp.Response.Data) < e.pageSize {
+			break
+		}
+	}
+	return flush()
+}
+
+func sortedKeys(fieldData map[string]interface{}) []string {
+	keys := make([]string, 0, len(fieldData))
+	for key := range fieldData {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,60 @@
+package filemaker
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestExporter_Export(t *testing.T) {
+	pages := map[string]string{
+		"1": `{"response":{"data":[{"fieldData":{"name":"pablo","city":"Santiago"},"recordId":"1","modId":"0"},{"fieldData":{"name":"ana","city":"Lima"},"recordId":"2","modId":"0"}]},"messages":[{"code":"0","message":"OK"}]}`,
+		"3": `{"response":{"data":[{"fieldData":{"name":"juan","city":"Quito"},"recordId":"3","modId":"0"}]},"messages":[{"code":"0","message":"OK"}]}`,
+	}
+	newExporter := func(t *testing.T) (*Exporter, *fakeServer) {
+		server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+			page, ok := pages[r.URL.Query().Get("_offset")]
+			if !ok || r.URL.Query().Get("_limit") != "2" {
+				t.Errorf("Unexpected page request %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(page))
+		})
+		return NewExporter(NewRecordService("test", "test_layout", newTestClient(t, server.URL)), 2), server
+	}
+
+	t.Run("Test CSV export", func(t *testing.T) {
+		exporter, server := newExporter(t)
+		var buf bytes.Buffer
+		if err := exporter.Export(context.Background(), &buf, CSVFormat); err != nil {
+			t.Fatalf("Export() error = %v", err)
+		}
+		want := "city,name\nSantiago,pablo\nLima,ana\nQuito,juan\n"
+		if buf.String() != want {
+			t.Errorf("CSV was incorrect, got: %q, want: %q", buf.String(), want)
+		}
+		if server.disconnectCount() != 1 {
+			t.Errorf("Disconnect calls was incorrect, got: %d, want: %d", server.disconnectCount(), 1)
+		}
+	})
+
+	t.Run("Test JSON lines export", func(t *testing.T) {
+		exporter, _ := newExporter(t)
+		var buf bytes.Buffer
+		if err := exporter.Export(context.Background(), &buf, JSONLinesFormat); err != nil {
+			t.Fatalf("Export() error = %v", err)
+		}
+		if lines := bytes.Count(buf.Bytes(), []byte("\n")); lines != 3 {
+			t.Errorf("Lines was incorrect, got: %d, want: %d", lines, 3)
+		}
+	})
+
+	t.Run("Test canceled export", func(t *testing.T) {
+		exporter, _ := newExporter(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err := exporter.Export(ctx, &bytes.Buffer{}, CSVFormat); err != context.Canceled {
+			t.Errorf("Export() error was incorrect, got: %v, want: %v", err, context.Canceled)
+		}
+	})
+}
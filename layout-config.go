@@ -0,0 +1,55 @@
+package filemaker
+
+// LayoutConfig holds the find defaults shared by every search against a
+// layout, so they are declared once:
+//
+//	orders := filemaker.NewLayoutConfig("Sales", "Orders").
+//		ResponseLayout("Orders_Compact").
+//		DateFormat(filemaker.ISO8601DateFormat)
+//	search := filemaker.NewSearchServiceFor(client, orders)
+//
+// Setters called on the search service afterwards override these defaults.
+type LayoutConfig struct {
+	database       string
+	layout         string
+	responseLayout string
+	dateFormat     DateFormat
+	portals        []string
+}
+
+func NewLayoutConfig(database, layout string) *LayoutConfig {
+	return &LayoutConfig{
+		database: database,
+		layout:   layout,
+	}
+}
+
+// ResponseLayout sets the layout used to build the response data.
+func (lc *LayoutConfig) ResponseLayout(layout string) *LayoutConfig {
+	lc.responseLayout = layout
+	return lc
+}
+
+func (lc *LayoutConfig) DateFormat(dateFormat DateFormat) *LayoutConfig {
+	lc.dateFormat = dateFormat
+	return lc
+}
+
+// Portals restricts the portals returned to names. All portals on the
+// layout are returned when none is set.
+func (lc *LayoutConfig) Portals(names ...string) *LayoutConfig {
+	lc.portals = names
+	return lc
+}
+
+// NewSearchServiceFor creates a search service for the database and layout
+// of config, with its defaults applied.
+func NewSearchServiceFor(client *Client, config *LayoutConfig) *searchService {
+	s := NewSearchService(config.database, config.layout, client)
+	s.seachData.ResponseLayout = config.responseLayout
+	s.seachData.DateFormat = config.dateFormat
+	if len(config.portals) > 0 {
+		s.seachData.Portal = append([]string(nil), config.portals...)
+	}
+	return s
+}
@@ -0,0 +1,67 @@
+package filemaker
+
+import (
+	"context"
+	"net/http"
+)
+
+// LayoutInfo is a layout of a database, or a folder of layouts.
+type LayoutInfo struct {
+	Name              string       `json:"name"`
+	Table             string       `json:"table,omitempty"`
+	IsFolder          bool         `json:"isFolder,omitempty"`
+	FolderLayoutNames []LayoutInfo `json:"folderLayoutNames,omitempty"`
+}
+
+// ScriptInfo is a script of a database, or a folder of scripts.
+type ScriptInfo struct {
+	Name              string       `json:"name"`
+	IsFolder          bool         `json:"isFolder,omitempty"`
+	FolderScriptNames []ScriptInfo `json:"folderScriptNames,omitempty"`
+}
+
+// Layouts returns the layouts of database, in the folders they are organized
+// in, under a session of its own.
+func (c *Client) Layouts(ctx context.Context, database string) ([]LayoutInfo, error) {
+	resp, err := c.metadata(ctx, database, "layouts")
+	if err != nil {
+		return nil, err
+	}
+	return resp.Response.Layouts, nil
+}
+
+// Scripts returns the scripts of database, in the folders they are organized
+// in, under a session of its own.
+func (c *Client) Scripts(ctx context.Context, database string) ([]ScriptInfo, error) {
+	resp, err := c.metadata(ctx, database, "scripts")
+	if err != nil {
+		return nil, err
+	}
+	return resp.Response.Scripts, nil
+}
+
+func (c *Client) metadata(ctx context.Context, database, segment string) (*ResponseData, error) {
+	ctx = c.operationContext(ctx)
+	responseAuth, err := c.connect(ctx, database)
+	if err != nil {
+		return nil, err
+	}
+	if err := responseAuth.Err(); err != nil {
+		return nil, err
+	}
+
+	defer c.disconnect(ctx, database, responseAuth.Response.Token)
+
+	resp, err := c.executeQuery(ctx, &performRequestOptions{
+		Method:  http.MethodGet,
+		Path:    c.dataPath("databases", database, segment),
+		Headers: authorizationHeader(responseAuth.Response.Token),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := resp.Err(); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
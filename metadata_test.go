@@ -0,0 +1,53 @@
+package filemaker
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestClient_Layouts(t *testing.T) {
+	var path string
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Write([]byte(`{"response":{"layouts":[{"name":"Orders","table":"Orders"},{"name":"Admin","isFolder":true,"folderLayoutNames":[{"name":"Users","table":"Users"}]}]},"messages":[{"code":"0","message":"OK"}]}`))
+	})
+
+	layouts, err := newTestClient(t, server.URL).Layouts(context.Background(), "Sales")
+	if err != nil {
+		t.Fatalf("Layouts() error = %v", err)
+	}
+	if path != "/fmi/data/vLatest/databases/Sales/layouts" {
+		t.Errorf("Path was incorrect, got: %s, want: %s", path, "/fmi/data/vLatest/databases/Sales/layouts")
+	}
+	if len(layouts) != 2 || layouts[0].Name != "Orders" || !layouts[1].IsFolder || layouts[1].FolderLayoutNames[0].Table != "Users" {
+		t.Errorf("Layouts() was incorrect, got: %+v", layouts)
+	}
+	if server.disconnectCount() != 1 {
+		t.Errorf("Disconnect calls was incorrect, got: %d, want: %d", server.disconnectCount(), 1)
+	}
+}
+
+func TestClient_Scripts(t *testing.T) {
+	t.Run("Test scripts", func(t *testing.T) {
+		server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"response":{"scripts":[{"name":"Nightly","isFolder":true,"folderScriptNames":[{"name":"Sync"}]},{"name":"Validate"}]},"messages":[{"code":"0","message":"OK"}]}`))
+		})
+		scripts, err := newTestClient(t, server.URL).Scripts(context.Background(), "Sales")
+		if err != nil {
+			t.Fatalf("Scripts() error = %v", err)
+		}
+		if len(scripts) != 2 || scripts[0].FolderScriptNames[0].Name != "Sync" || scripts[1].Name != "Validate" {
+			t.Errorf("Scripts() was incorrect, got: %+v", scripts)
+		}
+	})
+
+	t.Run("Test FileMaker error", func(t *testing.T) {
+		server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"response":{},"messages":[{"code":"9","message":"Insufficient privileges"}]}`))
+		})
+		if _, err := newTestClient(t, server.URL).Scripts(context.Background(), "Sales"); !IsPermissionError(err) {
+			t.Errorf("Scripts() error was incorrect, got: %v, want a permission error", err)
+		}
+	})
+}
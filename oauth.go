@@ -0,0 +1,133 @@
+package filemaker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// OAuthProvider is an OAuth identity provider configured on the server.
+type OAuthProvider struct {
+	Name        string `json:"Name"`
+	DisplayName string `json:"DisplayName"`
+	// Native tells a provider FileMaker Server supports out of the box,
+	// such as Google or Microsoft, from a custom one.
+	Native bool `json:"Native"`
+}
+
+// OAuthRequest is an OAuth login started with RequestOAuth.
+type OAuthRequest struct {
+	// RequestID is sent back to FileMaker Server by ConnectWithOAuth.
+	RequestID string
+	// URL is the provider login page to send the user to.
+	URL string
+}
+
+// GetOAuthProviders returns the OAuth identity providers configured on the
+// server, e.g. to render a login screen. Like Ping, it needs no session.
+func (c *Client) GetOAuthProviders(ctx context.Context) ([]OAuthProvider, error) {
+	_, data, err := c.fmwsRequest(ctx, "fmws/oauthproviderinfo", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var info struct {
+		Data struct {
+			Provider []OAuthProvider `json:"Provider"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, err
+	}
+	return info.Data.Provider, nil
+}
+
+// RequestOAuth starts an OAuth login with provider, one of the names returned
+// by GetOAuthProviders. Once the user logged in on OAuthRequest.URL, the
+// provider redirects to returnURL with the identifier to pass, along with
+// OAuthRequest.RequestID, to ConnectWithOAuth. trackingID is an id of the
+// caller's choice, echoed back in the redirect.
+func (c *Client) RequestOAuth(ctx context.Context, provider, trackingID, returnURL string) (*OAuthRequest, error) {
+	if provider == "" {
+		return nil, errors.New("Empty provider")
+	}
+	if returnURL == "" {
+		return nil, errors.New("Empty return URL")
+	}
+	base, err := url.Parse(c.url)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("trackingID", trackingID)
+	params.Set("provider", provider)
+	params.Set("address", base.Hostname())
+	params.Set("X-FMS-OAuth-AuthType", "2")
+	headers := http.Header{}
+	headers.Set("X-FMS-Application-Type", "9")
+	headers.Set("X-FMS-Application-Version", "15")
+	headers.Set("X-FMS-Return-URL", returnURL)
+
+	response, data, err := c.fmwsRequest(ctx, "oauth/getoauthurl", params, headers)
+	if err != nil {
+		return nil, err
+	}
+	requestID := response.Header.Get("X-FMS-Request-ID")
+	if requestID == "" {
+		return nil, errors.New("filemaker: the OAuth response has no request ID")
+	}
+	return &OAuthRequest{RequestID: requestID, URL: strings.TrimSpace(string(data))}, nil
+}
+
+// ConnectWithOAuth creates a session on database for the user who logged in
+// through the OAuth login requestID, identified by the identifier the
+// provider redirected with.
+func (c *Client) ConnectWithOAuth(ctx context.Context, database, requestID, identifier string) (*ResponseData, error) {
+	if requestID == "" {
+		return nil, errors.New("Empty OAuth request ID")
+	}
+	if identifier == "" {
+		return nil, errors.New("Empty OAuth identifier")
+	}
+
+	headers := http.Header{}
+	headers.Set("X-FM-Data-OAuth-Request-Id", requestID)
+	headers.Set("X-FM-Data-OAuth-Identifier", identifier)
+	return c.executeQuery(ctx, &performRequestOptions{
+		Method:      http.MethodPost,
+		Path:        c.dataPath("databases", database, "sessions"),
+		Body:        "{}",
+		ContentType: "application/json",
+		Headers:     headers,
+	})
+}
+
+// fmwsRequest sends a GET to an endpoint of FileMaker Server outside the Data
+// API, which doesn't answer with a ResponseData.
+func (c *Client) fmwsRequest(ctx context.Context, path string, params url.Values, headers http.Header) (*http.Response, []byte, error) {
+	response, err := c.performRequest(ctx, &performRequestOptions{
+		Method:  http.MethodGet,
+		Path:    path,
+		Params:  params,
+		Headers: headers,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if response == nil {
+		return nil, nil, errors.New("filemaker: no response")
+	}
+	defer response.Body.Close()
+
+	data, err := c.readBody(response.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, nil, newHTTPError(response, response.Header.Get("Content-Type"), data)
+	}
+	return response, data, nil
+}
@@ -0,0 +1,85 @@
+package filemaker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_GetOAuthProviders(t *testing.T) {
+	t.Run("Test providers", func(t *testing.T) {
+		var path, authorization string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path, authorization = r.URL.Path, r.Header.Get("Authorization")
+			w.Write([]byte(`{"data":{"Provider":[{"Name":"Google","DisplayName":"Sign in with Google","Native":true},{"Name":"Okta","DisplayName":"Okta","Native":false}]}}`))
+		}))
+		t.Cleanup(server.Close)
+
+		providers, err := newTestClient(t, server.URL).GetOAuthProviders(context.Background())
+		if err != nil {
+			t.Fatalf("GetOAuthProviders() error = %v", err)
+		}
+		if path != "/fmws/oauthproviderinfo" || authorization != "" {
+			t.Errorf("Request was incorrect, got: %s with authorization %q, want: %s without", path, authorization, "/fmws/oauthproviderinfo")
+		}
+		if len(providers) != 2 || providers[0].Name != "Google" || !providers[0].Native || providers[1].Native {
+			t.Errorf("GetOAuthProviders() was incorrect, got: %+v", providers)
+		}
+	})
+
+	t.Run("Test OAuth disabled", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		t.Cleanup(server.Close)
+
+		_, err := newTestClient(t, server.URL).GetOAuthProviders(context.Background())
+		if httpErr, ok := err.(*HTTPError); !ok || httpErr.StatusCode != http.StatusNotFound {
+			t.Errorf("GetOAuthProviders() error was incorrect, got: %v, want: %T", err, &HTTPError{})
+		}
+	})
+}
+
+func TestClient_OAuthLogin(t *testing.T) {
+	var sessionHeaders http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth/getoauthurl":
+			query := r.URL.Query()
+			if query.Get("provider") != "Google" || query.Get("trackingID") != "t-1" || query.Get("address") != "127.0.0.1" || r.Header.Get("X-FMS-Return-URL") != "https://app.example/callback" {
+				t.Errorf("OAuth request was incorrect, got: %s %v", r.URL.RawQuery, r.Header)
+			}
+			w.Header().Set("X-FMS-Request-ID", "req-42")
+			w.Write([]byte("https://accounts.google.com/o/oauth2/auth?state=t-1\n"))
+		case "/fmi/data/vLatest/databases/Sales/sessions":
+			sessionHeaders = r.Header
+			w.Write([]byte(`{"response":{"token":"oauth-token"},"messages":[{"code":"0","message":"OK"}]}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	client := newTestClient(t, server.URL)
+
+	request, err := client.RequestOAuth(context.Background(), "Google", "t-1", "https://app.example/callback")
+	if err != nil {
+		t.Fatalf("RequestOAuth() error = %v", err)
+	}
+	if request.RequestID != "req-42" || request.URL != "https://accounts.google.com/o/oauth2/auth?state=t-1" {
+		t.Errorf("RequestOAuth() was incorrect, got: %+v", request)
+	}
+
+	resp, err := client.ConnectWithOAuth(context.Background(), "Sales", request.RequestID, "ident-7")
+	if err != nil {
+		t.Fatalf("ConnectWithOAuth() error = %v", err)
+	}
+	if resp.Response.Token != "oauth-token" {
+		t.Errorf("Token was incorrect, got: %s, want: %s", resp.Response.Token, "oauth-token")
+	}
+	if sessionHeaders.Get("X-FM-Data-OAuth-Request-Id") != "req-42" || sessionHeaders.Get("X-FM-Data-OAuth-Identifier") != "ident-7" || sessionHeaders.Get("Authorization") != "" {
+		t.Errorf("Session headers were incorrect, got: %v", sessionHeaders)
+	}
+
+	if _, err := client.ConnectWithOAuth(context.Background(), "Sales", "", "ident-7"); err == nil {
+		t.Errorf("ConnectWithOAuth() without a request ID should fail")
+	}
+}
@@ -1,26 +1,60 @@
 package filemaker
 
 import (
+	"crypto/x509"
 	"errors"
+	"fmt"
+	"log"
 	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strings"
+	"time"
 )
 
 const (
-	DefaultVersion = "vLatest"
+	DefaultVersion  = "vLatest"
+	DefaultBasePath = "fmi/data"
 )
 
 type ClientOptions func(*Client) error
 
-func SetURL(url string) ClientOptions {
+// SetURL sets the server URL, e.g. "https://fm.example.com:443". Trailing
+// slashes and a trailing "/fmi/data" are removed, since the client adds the
+// base path itself.
+func SetURL(rawURL string) ClientOptions {
 	return func(c *Client) error {
-		if url == "" {
+		if rawURL == "" {
 			return errors.New("Empty url")
 		}
-		c.url = url
+		normalized, err := normalizeURL(rawURL)
+		if err != nil {
+			return err
+		}
+		c.url = normalized
 		return nil
 	}
 }
 
+func normalizeURL(rawURL string) (string, error) {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", fmt.Errorf("Invalid url: %v", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return "", fmt.Errorf("Invalid url scheme %q, want http or https", parsed.Scheme)
+	}
+	if parsed.Host == "" {
+		return "", errors.New("Invalid url: empty host")
+	}
+	if parsed.RawQuery != "" || parsed.Fragment != "" {
+		return "", errors.New("Invalid url: unexpected query or fragment")
+	}
+	path := strings.TrimRight(parsed.Path, "/")
+	path = strings.TrimSuffix(path, "/"+DefaultBasePath)
+	return parsed.Scheme + "://" + parsed.Host + strings.TrimRight(path, "/"), nil
+}
+
 func SetUsername(username string) ClientOptions {
 	return func(c *Client) error {
 		if username == "" {
@@ -41,6 +75,18 @@ func SetPassword(password string) ClientOptions {
 	}
 }
 
+// SetClarisIDToken sets the Claris ID token used to create sessions on
+// FileMaker Cloud, sent as "Authorization: FMID <token>" instead of Basic auth.
+func SetClarisIDToken(token string) ClientOptions {
+	return func(c *Client) error {
+		if token == "" {
+			return errors.New("Empty Claris ID token")
+		}
+		c.clarisIDToken = token
+		return nil
+	}
+}
+
 func SetVersion(version string) ClientOptions {
 	return func(c *Client) error {
 		if version == "" {
@@ -51,6 +97,19 @@ func SetVersion(version string) ClientOptions {
 	}
 }
 
+// SetBasePath sets the path prefix the Data API is mounted under, for servers
+// behind a reverse proxy. Defaults to fmi/data.
+func SetBasePath(basePath string) ClientOptions {
+	return func(c *Client) error {
+		basePath = strings.Trim(basePath, "/")
+		if basePath == "" {
+			return errors.New("Empty base path")
+		}
+		c.basePath = basePath
+		return nil
+	}
+}
+
 func SetHttpClient(httpClient *http.Client) ClientOptions {
 	return func(c *Client) error {
 		if httpClient == nil {
@@ -59,4 +118,192 @@ func SetHttpClient(httpClient *http.Client) ClientOptions {
 		c.httpClient = httpClient
 		return nil
 	}
-}
\ No newline at end of file
+}
+
+// SetMinTLSVersion sets the minimum TLS version accepted from the server,
+// e.g. tls.VersionTLS12. The http client's transport is copied, not
+// modified. It can't be combined with SetDoer.
+func SetMinTLSVersion(version uint16) ClientOptions {
+	return func(c *Client) error {
+		c.minTLSVersion = version
+		return nil
+	}
+}
+
+// SetPinnedCertificates fails the TLS handshake unless the certificate chain
+// of the server contains one of certs. The usual chain verification still
+// applies. The http client's transport is copied, not modified. It can't be
+// combined with SetDoer.
+func SetPinnedCertificates(certs []*x509.Certificate) ClientOptions {
+	return func(c *Client) error {
+		if len(certs) == 0 {
+			return errors.New("Empty pinned certificates")
+		}
+		c.pinnedCertificates = certs
+		return nil
+	}
+}
+
+// InsecureSkipTLSVerify disables the verification of the server certificate,
+// for local development servers with a self-signed certificate only. It
+// makes the connection open to man-in-the-middle attacks: NEVER use it in
+// production, pin the certificate with SetPinnedCertificates instead. A
+// warning is logged when the client is created. It can't be combined with
+// SetDoer.
+func InsecureSkipTLSVerify() ClientOptions {
+	return func(c *Client) error {
+		c.insecureSkipVerify = true
+		return nil
+	}
+}
+
+// SetMaxResponseBytes limits the size of the response bodies read by the
+// client. Zero, the default, means unlimited.
+func SetMaxResponseBytes(maxBytes int64) ClientOptions {
+	return func(c *Client) error {
+		if maxBytes < 0 {
+			return errors.New("Negative max response bytes")
+		}
+		c.maxResponseBytes = maxBytes
+		return nil
+	}
+}
+
+// SetDoer sends every request through doer instead of the http client, e.g.
+// a fake in unit tests or a wrapper adding tracing or rate limiting.
+func SetDoer(doer Doer) ClientOptions {
+	return func(c *Client) error {
+		if doer == nil {
+			return errors.New("Empty Doer")
+		}
+		c.doer = doer
+		return nil
+	}
+}
+
+// SetAcceptLanguage sends lang, e.g. "es", as the Accept-Language header of
+// every request so the server localizes the text of its messages.
+func SetAcceptLanguage(lang string) ClientOptions {
+	return func(c *Client) error {
+		if lang == "" {
+			return errors.New("Empty accept language")
+		}
+		c.acceptLanguage = lang
+		return nil
+	}
+}
+
+// SetRequestIDHeader sends a correlation id in the header name of every
+// request, e.g. "X-Request-ID". The id comes from ContextWithRequestID when
+// set and is generated otherwise.
+func SetRequestIDHeader(name string) ClientOptions {
+	return func(c *Client) error {
+		if name == "" {
+			return errors.New("Empty request ID header")
+		}
+		c.requestIDHeader = name
+		return nil
+	}
+}
+
+// SetRateLimit throttles the client to requestsPerSecond on average, allowing
+// bursts of up to burst requests. Requests, including the session calls,
+// wait for their turn until their context is done.
+func SetRateLimit(requestsPerSecond float64, burst int) ClientOptions {
+	return func(c *Client) error {
+		if requestsPerSecond <= 0 {
+			return errors.New("Invalid rate limit")
+		}
+		if burst < 1 {
+			return errors.New("Invalid rate limit burst")
+		}
+		c.rateLimiter = newRateLimiter(requestsPerSecond, burst)
+		return nil
+	}
+}
+
+// SetResponseFieldBlocklist removes fields from the fieldData of every record
+// once decoded, e.g. large text fields a list view never shows, so they
+// aren't retained. The Data API still sends them. A search's ExcludeFields
+// takes precedence.
+func SetResponseFieldBlocklist(fields []string) ClientOptions {
+	return func(c *Client) error {
+		c.responseFieldBlocklist = fields
+		return nil
+	}
+}
+
+// SetLogger sets the logger the client writes its warnings to. By default
+// only the InsecureSkipTLSVerify warning is logged, to the standard logger.
+func SetLogger(logger *log.Logger) ClientOptions {
+	return func(c *Client) error {
+		c.logger = logger
+		return nil
+	}
+}
+
+// SetSlowRequestThreshold logs, through the logger set with SetLogger, every
+// request whose response takes longer than threshold to arrive, with its
+// method and path. Slow finds often lack a limit on a large portal.
+func SetSlowRequestThreshold(threshold time.Duration) ClientOptions {
+	return func(c *Client) error {
+		if threshold < 0 {
+			return errors.New("Negative slow request threshold")
+		}
+		c.slowRequestThreshold = threshold
+		return nil
+	}
+}
+
+// SetTokenSource makes the services use the session token of source instead
+// of creating and closing a session per call. The sessions stay open: their
+// lifetime is up to the owner of source.
+func SetTokenSource(source TokenSource) ClientOptions {
+	return func(c *Client) error {
+		if source == nil {
+			return errors.New("Empty token source")
+		}
+		c.tokenSource = source
+		return nil
+	}
+}
+
+// SetRequestDumper registers dumper to be called with the raw body of every
+// request sent by the client, e.g. to log the exact JSON of a find.
+func SetRequestDumper(dumper RequestDumper) ClientOptions {
+	return func(c *Client) error {
+		c.requestDumper = dumper
+		return nil
+	}
+}
+
+// SetStrictScriptErrors makes requests fail with a ScriptStageError when their
+// pre-request or pre-sort script reports a non-zero error, so validation
+// scripts can block an operation. The response is still returned.
+func SetStrictScriptErrors(strict bool) ClientOptions {
+	return func(c *Client) error {
+		c.strictScripts = strict
+		return nil
+	}
+}
+
+// EnableRequestCoalescing makes concurrent identical reads (GetById, List and
+// finds with the same method, path, parameters and body) share one session
+// and one HTTP call. Writes are never coalesced. Callers sharing a call get
+// the same *ResponseData and must not modify it.
+func EnableRequestCoalescing() ClientOptions {
+	return func(c *Client) error {
+		c.readGroup = &callGroup{}
+		return nil
+	}
+}
+
+// SetClientTrace attaches trace to every request sent by the client, e.g. to
+// observe connection reuse through GotConnInfo.Reused or DNS, connect and TLS
+// timings. The hooks may be called concurrently.
+func SetClientTrace(trace *httptrace.ClientTrace) ClientOptions {
+	return func(c *Client) error {
+		c.clientTrace = trace
+		return nil
+	}
+}
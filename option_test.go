@@ -0,0 +1,34 @@
+package filemaker
+
+import "testing"
+
+func TestSetURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		want    string
+		wantErr bool
+	}{
+		{name: "plain", url: "https://fm.example.com", want: "https://fm.example.com"},
+		{name: "port", url: "http://10.0.0.5:8080", want: "http://10.0.0.5:8080"},
+		{name: "trailing slashes", url: "https://fm.example.com//", want: "https://fm.example.com"},
+		{name: "base path", url: "https://fm.example.com/fmi/data/", want: "https://fm.example.com"},
+		{name: "proxy prefix", url: "https://example.com/filemaker/fmi/data", want: "https://example.com/filemaker"},
+		{name: "missing scheme", url: "fm.example.com", wantErr: true},
+		{name: "wrong scheme", url: "ftp://fm.example.com", wantErr: true},
+		{name: "empty host", url: "https://", wantErr: true},
+		{name: "query", url: "https://fm.example.com?x=1", wantErr: true},
+		{name: "empty", url: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(SetURL(tt.url))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && client.url != tt.want {
+				t.Errorf("SetURL() was incorrect, got: %s, want: %s", client.url, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package filemaker
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// OrderedFields is field data that is serialized in the order fields were
+// set, for solutions where auto-enter calculations depend on evaluation order
+// and for byte-stable request bodies. Use it as Payload.FieldData. The zero
+// value is ready to use.
+type OrderedFields struct {
+	names  []string
+	values map[string]interface{}
+}
+
+func NewOrderedFields() *OrderedFields {
+	return &OrderedFields{values: make(map[string]interface{})}
+}
+
+// Set sets field to value. A field set again keeps its original position.
+func (f *OrderedFields) Set(field string, value interface{}) *OrderedFields {
+	if f.values == nil {
+		f.values = make(map[string]interface{})
+	}
+	if _, ok := f.values[field]; !ok {
+		f.names = append(f.names, field)
+	}
+	f.values[field] = value
+	return f
+}
+
+// Names returns the fields in the order they were set.
+func (f *OrderedFields) Names() []string {
+	return append([]string(nil), f.names...)
+}
+
+func (f *OrderedFields) Get(field string) (interface{}, bool) {
+	value, ok := f.values[field]
+	return value, ok
+}
+
+func (f *OrderedFields) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, name := range f.names {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(f.values[name])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
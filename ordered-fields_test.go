@@ -0,0 +1,38 @@
+package filemaker
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOrderedFields_MarshalJSON(t *testing.T) {
+	fields := NewOrderedFields().
+		Set("zeta", "1").
+		Set("alpha", 2).
+		Set("Orders::Status", "open").
+		Set("zeta", "3")
+
+	b, err := json.Marshal(&Payload{FieldData: fields})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	want := "{\"fieldData\":{\"zeta\":\"3\",\"alpha\":2,\"Orders::Status\":\"open\"}}"
+	if string(b) != want {
+		t.Errorf("JSON was incorrect, got: %s, want: %s", string(b), want)
+	}
+
+	t.Run("Test empty fields", func(t *testing.T) {
+		b, _ := json.Marshal(NewOrderedFields())
+		if string(b) != "{}" {
+			t.Errorf("JSON was incorrect, got: %s, want: %s", string(b), "{}")
+		}
+	})
+
+	t.Run("Test zero value", func(t *testing.T) {
+		var fields OrderedFields
+		b, _ := json.Marshal(fields.Set("b", 1).Set("a", 2))
+		if string(b) != `{"b":1,"a":2}` {
+			t.Errorf("JSON was incorrect, got: %s, want: %s", string(b), `{"b":1,"a":2}`)
+		}
+	})
+}
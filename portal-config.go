@@ -0,0 +1,92 @@
+package filemaker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// PortalConfig selects a portal to return with GetById and List and pages
+// through its related rows. Without a limit or offset the Data API default
+// applies.
+type PortalConfig struct {
+	Name      string
+	limit     int
+	offset    int
+	hasLimit  bool
+	hasOffset bool
+}
+
+func NewPortalConfig(name string) *PortalConfig {
+	return &PortalConfig{Name: name}
+}
+
+// WithLimit sets the maximum number of related rows returned. A limit of 0
+// returns the portal without rows, unlike an unset limit.
+func (pc *PortalConfig) WithLimit(limit int) *PortalConfig {
+	pc.limit = limit
+	pc.hasLimit = true
+	return pc
+}
+
+// WithOffset sets the first related row returned, starting at 1.
+func (pc *PortalConfig) WithOffset(offset int) *PortalConfig {
+	pc.offset = offset
+	pc.hasOffset = true
+	return pc
+}
+
+// Validate checks that the portal has a name and a valid limit and offset.
+func (pc *PortalConfig) Validate() error {
+	if pc.Name == "" {
+		return &ValidationError{Field: "portal", Message: "empty portal name"}
+	}
+	if pc.hasLimit && pc.limit < 0 {
+		return &ValidationError{Field: "portal", Message: fmt.Sprintf("limit %d of portal %s must not be negative", pc.limit, pc.Name)}
+	}
+	if pc.hasOffset && pc.offset < 1 {
+		return &ValidationError{Field: "portal", Message: fmt.Sprintf("offset %d of portal %s must be at least 1", pc.offset, pc.Name)}
+	}
+	return nil
+}
+
+// ToQueryParams returns the _limit.<portal> and _offset.<portal> parameters
+// of a GET request.
+func (pc *PortalConfig) ToQueryParams() url.Values {
+	params := url.Values{}
+	if pc.hasLimit {
+		params.Set("_limit."+pc.Name, strconv.Itoa(pc.limit))
+	}
+	if pc.hasOffset {
+		params.Set("_offset."+pc.Name, strconv.Itoa(pc.offset))
+	}
+	return params
+}
+
+func validatePortals(portals []*PortalConfig) error {
+	for _, portal := range portals {
+		if portal == nil {
+			return &ValidationError{Field: "portal", Message: "nil portal"}
+		}
+		if err := portal.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addPortalParams(params url.Values, portals []*PortalConfig) {
+	if len(portals) == 0 {
+		return
+	}
+	names := make([]string, 0, len(portals))
+	for _, portal := range portals {
+		names = append(names, portal.Name)
+		for key, values := range portal.ToQueryParams() {
+			params[key] = values
+		}
+	}
+	data, _ := json.Marshal(names)
+	params.Set("portal", string(data))
+}
@@ -0,0 +1,107 @@
+package filemaker
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestPortalConfig_ToQueryParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		portal *PortalConfig
+		want   string
+	}{
+		{name: "no paging", portal: NewPortalConfig("LineItems"), want: ""},
+		{name: "limit", portal: NewPortalConfig("LineItems").WithLimit(50), want: "_limit.LineItems=50"},
+		{name: "zero limit", portal: NewPortalConfig("LineItems").WithLimit(0), want: "_limit.LineItems=0"},
+		{name: "limit and offset", portal: NewPortalConfig("LineItems").WithLimit(50).WithOffset(101), want: "_limit.LineItems=50&_offset.LineItems=101"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.portal.ToQueryParams().Encode(); got != tt.want {
+				t.Errorf("ToQueryParams() was incorrect, got: %s, want: %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPortalConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		portal  *PortalConfig
+		wantErr bool
+	}{
+		{name: "valid", portal: NewPortalConfig("LineItems").WithLimit(10).WithOffset(1)},
+		{name: "zero limit", portal: NewPortalConfig("LineItems").WithLimit(0)},
+		{name: "empty name", portal: NewPortalConfig(""), wantErr: true},
+		{name: "negative limit", portal: NewPortalConfig("LineItems").WithLimit(-1), wantErr: true},
+		{name: "zero offset", portal: NewPortalConfig("LineItems").WithOffset(0), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.portal.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_recordService_Portals(t *testing.T) {
+	var query map[string][]string
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{"response":{"data":[]},"messages":[{"code":"0","message":"OK"}]}`))
+	})
+	service := NewRecordService("test", "test_layout", newTestClient(t, server.URL)).
+		Portals(NewPortalConfig("LineItems").WithLimit(20).WithOffset(41), NewPortalConfig("Notes"))
+
+	t.Run("Test GetById", func(t *testing.T) {
+		if _, err := service.GetById("7"); err != nil {
+			t.Fatalf("GetById() error = %v", err)
+		}
+		if got := query["portal"]; len(got) != 1 || got[0] != `["LineItems","Notes"]` {
+			t.Errorf("portal was incorrect, got: %v, want: %v", got, `["LineItems","Notes"]`)
+		}
+		if got := query["_limit.LineItems"]; len(got) != 1 || got[0] != "20" {
+			t.Errorf("_limit.LineItems was incorrect, got: %v, want: %v", got, "20")
+		}
+		if got := query["_offset.LineItems"]; len(got) != 1 || got[0] != "41" {
+			t.Errorf("_offset.LineItems was incorrect, got: %v, want: %v", got, "41")
+		}
+		if _, ok := query["_limit.Notes"]; ok {
+			t.Errorf("_limit.Notes should not be sent")
+		}
+	})
+
+	t.Run("Test List", func(t *testing.T) {
+		if _, err := service.List("1", "10"); err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		if got := query["_limit.LineItems"]; len(got) != 1 || got[0] != "20" {
+			t.Errorf("_limit.LineItems was incorrect, got: %v, want: %v", got, "20")
+		}
+		if got := query["_limit"]; len(got) != 1 || got[0] != "10" {
+			t.Errorf("_limit was incorrect, got: %v, want: %v", got, "10")
+		}
+	})
+
+	t.Run("Test zero limit", func(t *testing.T) {
+		empty := NewRecordService("test", "test_layout", service.client).Portals(NewPortalConfig("LineItems").WithLimit(0))
+		if _, err := empty.GetById("7"); err != nil {
+			t.Fatalf("GetById() error = %v", err)
+		}
+		if got := query["_limit.LineItems"]; len(got) != 1 || got[0] != "0" {
+			t.Errorf("_limit.LineItems was incorrect, got: %v, want: %v", got, "0")
+		}
+		if got := query["portal"]; len(got) != 1 || got[0] != `["LineItems"]` {
+			t.Errorf("portal was incorrect, got: %v, want: %v", got, `["LineItems"]`)
+		}
+	})
+
+	t.Run("Test invalid portal", func(t *testing.T) {
+		invalid := NewRecordService("test", "test_layout", nil).Portals(NewPortalConfig("LineItems").WithOffset(0))
+		if _, err := invalid.GetById("7"); err == nil {
+			t.Errorf("GetById() with an invalid portal should fail")
+		}
+	})
+}
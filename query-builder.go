@@ -0,0 +1,42 @@
+package filemaker
+
+// QueryBuilder composes the find requests of a search. FileMaker ORs the
+// find requests and ANDs the fields within each, so
+//
+//	filemaker.Q().
+//		And(statusOpen, regionNorth).
+//		Or(statusLate, regionSouth, priorityHigh)
+//
+// finds (status open AND region north) OR (status late AND region south AND
+// priority high).
+type QueryBuilder struct {
+	groups []*groupQuery
+}
+
+func Q() *QueryBuilder {
+	return &QueryBuilder{}
+}
+
+// And adds queries to the current find request, starting the first one if
+// needed.
+func (q *QueryBuilder) And(queries ...*queryFieldOperator) *QueryBuilder {
+	if len(q.groups) == 0 {
+		return q.Or(queries...)
+	}
+	current := q.groups[len(q.groups)-1]
+	for _, query := range queries {
+		current.AddQuery(query)
+	}
+	return q
+}
+
+// Or starts a new find request holding queries.
+func (q *QueryBuilder) Or(queries ...*queryFieldOperator) *QueryBuilder {
+	q.groups = append(q.groups, NewGroupQuery(queries...))
+	return q
+}
+
+// Groups returns the find requests built so far.
+func (q *QueryBuilder) Groups() []*groupQuery {
+	return q.groups
+}
@@ -0,0 +1,42 @@
+package filemaker
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestQueryBuilder(t *testing.T) {
+	tests := []struct {
+		name  string
+		query *QueryBuilder
+		want  string
+	}{
+		{
+			name:  "and only",
+			query: Q().And(NewQueryFieldOperator("a", "1", Equal), NewQueryFieldOperator("b", "2", Equal)),
+			want:  `[{"a":"==1","b":"==2"}]`,
+		},
+		{
+			name: "or of and groups",
+			query: Q().
+				And(NewQueryFieldOperator("a", "1", Equal), NewQueryFieldOperator("b", "2", Equal)).
+				Or(NewQueryFieldOperator("c", "3", Equal)).
+				And(NewQueryFieldOperator("d", "4", Equal), NewQueryFieldOperator("e", "5", Equal)),
+			want: `[{"a":"==1","b":"==2"},{"c":"==3","d":"==4","e":"==5"}]`,
+		},
+		{
+			name:  "or only",
+			query: Q().Or(NewQueryFieldOperator("a", "1", Equal)).Or(NewQueryFieldOperator("a", "2", Equal)),
+			want:  `[{"a":"==1"},{"a":"==2"}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			search := NewSearchService("test", "test_layout", nil).WhereGroup(tt.query)
+			got, _ := json.Marshal(search.seachData.QueryGroup)
+			if string(got) != tt.want {
+				t.Errorf("Query was incorrect, got: %s, want: %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package filemaker
 
+import "strings"
+
 type FieldOperator string
 
 const (
@@ -11,8 +13,28 @@ const (
 	GreaterThanEqual FieldOperator = "gte"
 	LessThan         FieldOperator = "lt"
 	LessThanEqual    FieldOperator = "lte"
+	// EqualLiteral matches the whole field value literally, escaping every
+	// FileMaker find operator in the value.
+	EqualLiteral FieldOperator = "eql"
 )
 
+// findOperators are the characters FileMaker interprets as operators inside
+// find values.
+const findOperators = `\@*#?!=<>"~`
+
+// EscapeFindValue backslash-escapes the FileMaker find operators in value so
+// it is matched literally, e.g. "a*b" only matches "a*b".
+func EscapeFindValue(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if strings.ContainsRune(findOperators, r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
 type queryFieldOperator struct {
 	Name     string
 	Value    string
@@ -31,6 +53,8 @@ func (qf *queryFieldOperator) valueWithOp() string {
 	switch qf.Operator {
 	case Equal:
 		return "==" + qf.Value
+	case EqualLiteral:
+		return "==" + EscapeFindValue(qf.Value)
 	case Contains:
 		return "==*" + qf.Value + "*"
 	case BeginsWith:
@@ -0,0 +1,37 @@
+package filemaker
+
+import "testing"
+
+func TestEscapeFindValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "plain text", want: "plain text"},
+		{value: "a*b", want: `a\*b`},
+		{value: "user@example.com", want: `user\@example.com`},
+		{value: "#1", want: `\#1`},
+		{value: "what?", want: `what\?`},
+		{value: "!dup", want: `\!dup`},
+		{value: "=x", want: `\=x`},
+		{value: "<5>", want: `\<5\>`},
+		{value: `"quoted"`, want: `\"quoted\"`},
+		{value: "~fuzzy", want: `\~fuzzy`},
+		{value: `back\slash`, want: `back\\slash`},
+		{value: `@*#?!=<>"~\`, want: `\@\*\#\?\!\=\<\>\"\~\\`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := EscapeFindValue(tt.value); got != tt.want {
+				t.Errorf("EscapeFindValue() was incorrect, got: %s, want: %s", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("Test literal operator", func(t *testing.T) {
+		got := NewQueryFieldOperator("sku", "a*b", EqualLiteral).valueWithOp()
+		if got != `==a\*b` {
+			t.Errorf("Value was incorrect, got: %s, want: %s", got, `==a\*b`)
+		}
+	})
+}
@@ -1,5 +1,11 @@
 package filemaker
 
+// groupQuery is one FileMaker find request: its field predicates are ANDed,
+// while the groups given to GroupQueries are ORed.
+//
+// A find request holds a single criterion per field, so when a field is added
+// more than once to the same group the last query wins. Express ranges with
+// one value (e.g. "1...10") and alternatives with separate groups.
 type groupQuery struct {
 	queries []*queryFieldOperator
 }
@@ -7,3 +13,10 @@ type groupQuery struct {
 func NewGroupQuery(queries ...*queryFieldOperator) *groupQuery {
 	return &groupQuery{queries: queries}
 }
+
+// AddQuery adds query to the group, replacing any previous query on the same
+// field.
+func (gq *groupQuery) AddQuery(query *queryFieldOperator) *groupQuery {
+	gq.queries = append(gq.queries, query)
+	return gq
+}
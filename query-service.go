@@ -1,10 +1,15 @@
 package filemaker
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type RecordService interface {
@@ -16,12 +21,16 @@ type RecordService interface {
 	List(offset, limit string, sorters ...*Sorter) (*ResponseData, error)
 }
 
-const recordsPath = "fmi/data/%s/databases/%s/layouts/%s/records"
-
 type recordService struct {
 	database string
 	layout   string
 	client   *Client
+	fields   []string
+	portals  []*PortalConfig
+	// lockAttempts and lockDelay retry edits of a locked record.
+	lockAttempts int
+	lockDelay    time.Duration
+	params       url.Values
 }
 
 func NewRecordService(database, layout string, client *Client) *recordService {
@@ -32,132 +41,543 @@ func NewRecordService(database, layout string, client *Client) *recordService {
 	}
 }
 
+// Fields restricts the fieldData returned by GetById and List to names.
+// The Data API always sends every field on the layout, so this only trims the
+// decoded records client side; use a layout with fewer fields to reduce the
+// response size on the wire.
+func (s *recordService) Fields(names ...string) *recordService {
+	s.fields = names
+	return s
+}
+
+// Portals restricts the portals returned by GetById and List to portals and
+// pages through their related rows.
+func (s *recordService) Portals(portals ...*PortalConfig) *recordService {
+	s.portals = portals
+	return s
+}
+
+// SetParam adds a query parameter to GetById and List the service doesn't
+// model, e.g. one of a newer Data API version. It never overrides a
+// parameter set by the service, and an unknown parameter can make the server
+// fail the request.
+func (s *recordService) SetParam(key, value string) *recordService {
+	if s.params == nil {
+		s.params = url.Values{}
+	}
+	s.params.Set(key, value)
+	return s
+}
+
+// RetryOnLock makes edits retry up to attempts times in total, waiting delay
+// between tries, while the record is in use by another user (error 301).
+// The wait ends early when the context of the edit is done. Edits aren't
+// retried by default.
+func (s *recordService) RetryOnLock(attempts int, delay time.Duration) *recordService {
+	s.lockAttempts = attempts
+	s.lockDelay = delay
+	return s
+}
+
+// Clone returns a copy of the service that can be configured independently.
+func (s *recordService) Clone() *recordService {
+	clone := *s
+	if s.fields != nil {
+		clone.fields = append([]string(nil), s.fields...)
+	}
+	if s.portals != nil {
+		clone.portals = make([]*PortalConfig, len(s.portals))
+		for i, portal := range s.portals {
+			if portal != nil {
+				portalCopy := *portal
+				clone.portals[i] = &portalCopy
+			}
+		}
+	}
+	clone.params = copyParams(s.params)
+	return &clone
+}
+
 type Payload struct {
 	FieldData  interface{} `json:"fieldData"`
 	PortalData interface{} `json:"portalData,omitempty"`
+	// ModID makes an edit fail unless the record is still at this
+	// modification ID (optimistic locking).
+	ModID string `json:"modId,omitempty"`
 }
 
 func (s *recordService) Create(payload *Payload) (*ResponseData, error) {
 
-	responseAuth, err := s.client.Connect(s.database)
+	ctx := s.client.operationContext(context.Background())
+	responseAuth, err := s.client.connect(ctx, s.database)
+	if err != nil {
+		return nil, err
+	}
+
+	defer s.client.disconnect(ctx, s.database, responseAuth.Response.Token)
+
+	return s.create(ctx, responseAuth.Response.Token, payload)
+}
+
+// CreateResult identifies the record created by CreateRecord.
+type CreateResult struct {
+	RecordID string
+	ModID    string
+}
+
+// CreateRecord is like Create but returns the IDs of the new record, and the
+// FileMaker error when the create failed.
+func (s *recordService) CreateRecord(payload *Payload) (*CreateResult, error) {
+	resp, err := s.Create(payload)
+	if err != nil {
+		return nil, err
+	}
+	if err := resp.Err(); err != nil {
+		return nil, err
+	}
+	return &CreateResult{RecordID: resp.Response.RecordID, ModID: resp.Response.ModID}, nil
+}
+
+// CreateIdempotent creates a record unless one with the same uniqueField value
+// already exists, in which case the existing record is returned instead. It
+// never edits, so retrying a create that timed out can't produce duplicates.
+// The value is matched literally, find operators included, and a failed
+// lookup returns its error without creating.
+// The returned response carries the recordId and modId of the record that was
+// created or found.
+func (s *recordService) CreateIdempotent(uniqueField string, payload *Payload) (*ResponseData, error) {
+	value, err := fieldValue(payload, uniqueField)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := s.client.operationContext(context.Background())
+	responseAuth, err := s.client.connect(ctx, s.database)
+	if err != nil {
+		return nil, err
+	}
+
+	defer s.client.disconnect(ctx, s.database, responseAuth.Response.Token)
+
+	search := NewSearchService(s.database, s.layout, s.client).
+		GroupQueries(NewGroupQuery(NewQueryFieldOperator(uniqueField, value, EqualLiteral))).
+		SetLimit("1")
+	found, err := search.find(ctx, responseAuth.Response.Token)
+	if err != nil {
+		return nil, err
+	}
+	if err := found.Err(); err != nil && !IsNoRecordsError(err) {
+		return found, err
+	}
+	if found != nil && len(found.Response.Data) > 0 {
+		existing := found.Response.Data[0]
+		found.Response.RecordID = existing.RecordID
+		found.Response.ModID = existing.ModID
+		return found, nil
+	}
+
+	return s.create(ctx, responseAuth.Response.Token, payload)
+}
+
+// ImportTransactional creates a record for each payload in a single session
+// and returns their IDs in order. When a create fails, or ctx is done, the
+// records created so far are deleted again and an *ImportError is returned.
+// The Data API has no transactions, so this is best effort: other clients
+// can see the records before the rollback and a failed delete leaves them in
+// place, as listed in ImportError.Rollback. The rollback still runs when ctx
+// is done, each delete bounded like Disconnect.
+func (s *recordService) ImportTransactional(ctx context.Context, payloads []*Payload) ([]string, error) {
+	ctx = s.client.operationContext(ctx)
+	responseAuth, err := s.client.connect(ctx, s.database)
 	if err != nil {
 		return nil, err
 	}
 
-	defer s.client.Disconnect(s.database, responseAuth.Response.Token)
+	defer s.client.disconnect(ctx, s.database, responseAuth.Response.Token)
+
+	token := responseAuth.Response.Token
+	created := make([]string, 0, len(payloads))
+	for i, payload := range payloads {
+		err := ctx.Err()
+		if err == nil {
+			var resp *ResponseData
+			resp, err = s.create(ctx, token, payload)
+			if err == nil {
+				err = resp.Err()
+			}
+			if err == nil {
+				created = append(created, resp.Response.RecordID)
+				continue
+			}
+		}
+		return nil, &ImportError{Index: i, Err: err, Rollback: s.rollback(ctx, token, created)}
+	}
+	return created, nil
+}
+
+// rollback deletes recordIds and returns the deletes that failed, or nil. It
+// runs detached from the deadline and cancellation of ctx, which may be the
+// reason for the rollback.
+func (s *recordService) rollback(ctx context.Context, token string, recordIds []string) *BatchError {
+	batchErr := &BatchError{Errors: make(map[string]error)}
+	for _, recordId := range recordIds {
+		deleteCtx, cancel := context.WithTimeout(detachRequestID(ctx), disconnectTimeout)
+		resp, err := s.delete(deleteCtx, token, recordId)
+		cancel()
+		if err == nil {
+			err = resp.Err()
+		}
+		if err != nil {
+			batchErr.Errors[recordId] = err
+		}
+	}
+	if len(batchErr.Errors) > 0 {
+		return batchErr
+	}
+	return nil
+}
 
-	path := fmt.Sprintf(recordsPath, s.client.version, s.database, s.layout)
+func (s *recordService) create(ctx context.Context, token string, payload *Payload) (*ResponseData, error) {
+	path := s.recordsPath()
 	options := &performRequestOptions{
 		Method:      http.MethodPost,
 		Path:        path,
 		ContentType: "application/json",
 		Body:        payload,
-		Headers: http.Header{
-			"Authorization": []string{fmt.Sprintf("Bearer %s", responseAuth.Response.Token)},
-		},
+		Headers:     authorizationHeader(token),
 	}
 
-	return s.client.executeQuery(options)
+	return s.client.executeQuery(ctx, options)
 }
 
 func (s *recordService) Edit(recordId string, payload *Payload) (*ResponseData, error) {
+	if err := validateRecordID(recordId); err != nil {
+		return nil, err
+	}
+	if payload != nil && payload.ModID != "" {
+		if err := validateNumericID("modId", payload.ModID); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx := s.client.operationContext(context.Background())
+	responseAuth, err := s.client.connect(ctx, s.database)
+	if err != nil {
+		return nil, err
+	}
+
+	defer s.client.disconnect(ctx, s.database, responseAuth.Response.Token)
+
+	return s.edit(ctx, responseAuth.Response.Token, recordId, payload)
+}
 
-	responseAuth, err := s.client.Connect(s.database)
+// EditRaw edits recordId sending body as-is, for Data API parameters the
+// package doesn't model yet. The raw body bypasses every validation and is
+// not checked for correctness.
+func (s *recordService) EditRaw(recordId string, body json.RawMessage) (*ResponseData, error) {
+	if err := validateRecordID(recordId); err != nil {
+		return nil, err
+	}
+	ctx := s.client.operationContext(context.Background())
+	responseAuth, err := s.client.connect(ctx, s.database)
 	if err != nil {
 		return nil, err
 	}
 
-	defer s.client.Disconnect(s.database, responseAuth.Response.Token)
+	defer s.client.disconnect(ctx, s.database, responseAuth.Response.Token)
+
+	return s.edit(ctx, responseAuth.Response.Token, recordId, body)
+}
+
+// EditChanged edits recordId sending only the fields of payload whose value
+// differs from original, so untouched fields don't trigger auto-enter or
+// validation again. Values are compared by their string representation, the
+// way FileMaker stores them. Fields missing from payload are left untouched.
+// When no field changed and there is no portal data, no request is sent and
+// EditChanged returns a successful response carrying recordId.
+func (s *recordService) EditChanged(recordId string, original map[string]interface{}, payload *Payload) (*ResponseData, error) {
+	if err := validateRecordID(recordId); err != nil {
+		return nil, err
+	}
+	if payload == nil {
+		return nil, errors.New("Empty payload")
+	}
+	fieldData, err := fieldDataMap(payload.FieldData)
+	if err != nil {
+		return nil, err
+	}
 
-	path := fmt.Sprintf(recordsPath+"/%s", s.client.version, s.database, s.layout, recordId)
+	unchanged := func(name string, value interface{}) bool {
+		previous, ok := original[name]
+		return ok && fmt.Sprint(previous) == fmt.Sprint(value)
+	}
+
+	diff := *payload
+	changedCount := 0
+	if ordered, ok := payload.FieldData.(*OrderedFields); ok {
+		changed := NewOrderedFields()
+		for _, name := range ordered.names {
+			if !unchanged(name, ordered.values[name]) {
+				changed.Set(name, ordered.values[name])
+			}
+		}
+		changedCount = len(changed.names)
+		diff.FieldData = changed
+	} else {
+		changed := make(map[string]interface{})
+		for name, value := range fieldData {
+			if !unchanged(name, value) {
+				changed[name] = value
+			}
+		}
+		changedCount = len(changed)
+		diff.FieldData = changed
+	}
+	if changedCount == 0 && payload.PortalData == nil {
+		resp := &ResponseData{Messages: []Message{{Code: "0", Message: "OK"}}}
+		resp.Response.RecordID = recordId
+		resp.Response.ModID = payload.ModID
+		return resp, nil
+	}
+
+	return s.Edit(recordId, &diff)
+}
+
+// EditDatum edits the record datum was read from, locked on the modId it was
+// read at, so the edit fails with FileMaker error 306 if someone changed the
+// record in between. A ModID already set in payload is kept.
+func (s *recordService) EditDatum(datum *Datum, payload *Payload) (*ResponseData, error) {
+	if datum == nil || datum.RecordID == "" {
+		return nil, &ValidationError{Field: "recordId", Message: "datum has no record ID"}
+	}
+	if payload == nil {
+		return nil, errors.New("Empty payload")
+	}
+	locked := *payload
+	if locked.ModID == "" {
+		locked.ModID = datum.ModID
+	}
+	return s.Edit(datum.RecordID, &locked)
+}
+
+// validateRecordID rejects a record ID that isn't an integer, the only form
+// FileMaker uses, e.g. a business key passed by mistake.
+func validateRecordID(recordId string) error {
+	return validateNumericID("recordId", recordId)
+}
+
+func validateNumericID(field, id string) error {
+	if _, err := strconv.ParseInt(id, 10, 64); err != nil {
+		return &ValidationError{Field: field, Message: fmt.Sprintf("%q is not a numeric ID", id)}
+	}
+	return nil
+}
+
+func (s *recordService) edit(ctx context.Context, token, recordId string, payload interface{}) (*ResponseData, error) {
+	path := s.recordsPath(recordId)
 	options := &performRequestOptions{
 		Method:      http.MethodPatch,
 		Path:        path,
 		ContentType: "application/json",
 		Body:        payload,
-		Headers: http.Header{
-			"Authorization": []string{fmt.Sprintf("Bearer %s", responseAuth.Response.Token)},
-		},
+		Headers:     authorizationHeader(token),
 	}
 
-	return s.client.executeQuery(options)
-
+	for attempt := 1; ; attempt++ {
+		resp, err := s.client.executeQuery(ctx, options)
+		if err != nil || attempt >= s.lockAttempts || !IsLockError(resp.Err()) {
+			return resp, err
+		}
+		timer := time.NewTimer(s.lockDelay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
 }
 
+// Duplicate duplicates the record and returns the recordId and modId of the
+// copy, which is all the Data API reports. It offers no control over related
+// records: like Duplicate Record in FileMaker, only the record itself is
+// copied, so portal rows are duplicated only by a script or by auto-enter
+// options on the related table. Find them afterwards to get their IDs.
 func (s *recordService) Duplicate(recordId string) (*ResponseData, error) {
-	responseAuth, err := s.client.Connect(s.database)
+	if err := validateRecordID(recordId); err != nil {
+		return nil, err
+	}
+	ctx := s.client.operationContext(context.Background())
+	responseAuth, err := s.client.connect(ctx, s.database)
 	if err != nil {
 		return nil, err
 	}
 
-	defer s.client.Disconnect(s.database, responseAuth.Response.Token)
+	defer s.client.disconnect(ctx, s.database, responseAuth.Response.Token)
 
-	path := fmt.Sprintf(recordsPath+"/%s", s.client.version, s.database, s.layout, recordId)
+	path := s.recordsPath(recordId)
 	options := &performRequestOptions{
 		Method:      http.MethodPost,
 		Path:        path,
 		ContentType: "application/json",
-		Headers: http.Header{
-			"Authorization": []string{fmt.Sprintf("Bearer %s", responseAuth.Response.Token)},
-		},
+		Headers:     authorizationHeader(responseAuth.Response.Token),
 	}
 
-	return s.client.executeQuery(options)
+	return s.client.executeQuery(ctx, options)
 }
 
 func (s *recordService) Delete(recordId string) (*ResponseData, error) {
+	if err := validateRecordID(recordId); err != nil {
+		return nil, err
+	}
 
-	responseAuth, err := s.client.Connect(s.database)
+	ctx := s.client.operationContext(context.Background())
+	responseAuth, err := s.client.connect(ctx, s.database)
 	if err != nil {
 		return nil, err
 	}
 
-	defer s.client.Disconnect(s.database, responseAuth.Response.Token)
+	defer s.client.disconnect(ctx, s.database, responseAuth.Response.Token)
 
-	path := fmt.Sprintf(recordsPath+"/%s", s.client.version, s.database, s.layout, recordId)
-	options := &performRequestOptions{
-		Method: http.MethodDelete,
-		Path:   path,
-		Headers: http.Header{
-			"Authorization": []string{fmt.Sprintf("Bearer %s", responseAuth.Response.Token)},
-		},
+	return s.delete(ctx, responseAuth.Response.Token, recordId)
+}
+
+// DeleteByQuery deletes every record matching queryGroups under the session
+// token and returns the IDs of the deleted records, so callers managing their
+// own session can reuse it. An empty token makes DeleteByQuery open a session
+// of its own. The whole found set is collected before deleting. When some
+// deletes fail, the IDs that were deleted are returned along with a
+// *BatchError holding the failures.
+func (s *recordService) DeleteByQuery(ctx context.Context, queryGroups []*groupQuery, token string) ([]string, error) {
+	if len(queryGroups) == 0 {
+		return nil, &ValidationError{Field: "query", Message: "at least one query group is required"}
 	}
 
-	return s.client.executeQuery(options)
+	ctx = s.client.operationContext(ctx)
+	if token == "" {
+		responseAuth, err := s.client.connect(ctx, s.database)
+		if err != nil {
+			return nil, err
+		}
 
-}
-func (s *recordService) GetById(recordId string) (*ResponseData, error) {
+		defer s.client.disconnect(ctx, s.database, responseAuth.Response.Token)
+
+		token = responseAuth.Response.Token
+	}
 
-	responseAuth, err := s.client.Connect(s.database)
+	var recordIds []string
+	search := NewSearchService(s.database, s.layout, s.client).GroupQueries(queryGroups...)
+	err := search.findPages(ctx, token, findPageSize, func(data []Datum) error {
+		for _, datum := range data {
+			recordIds = append(recordIds, datum.RecordID)
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	defer s.client.Disconnect(s.database, responseAuth.Response.Token)
+	deleted := make([]string, 0, len(recordIds))
+	batchErr := &BatchError{Errors: make(map[string]error)}
+	for _, recordId := range recordIds {
+		resp, err := s.delete(ctx, token, recordId)
+		if err == nil {
+			err = resp.Err()
+		}
+		if err != nil {
+			batchErr.Errors[recordId] = err
+			continue
+		}
+		deleted = append(deleted, recordId)
+	}
+	if len(batchErr.Errors) > 0 {
+		return deleted, batchErr
+	}
+	return deleted, nil
+}
 
-	path := fmt.Sprintf(recordsPath+"/%s", s.client.version, s.database, s.layout, recordId)
+func (s *recordService) delete(ctx context.Context, token, recordId string) (*ResponseData, error) {
+	path := s.recordsPath(recordId)
 	options := &performRequestOptions{
-		Method: http.MethodGet,
-		Path:   path,
-		Headers: http.Header{
-			"Authorization": []string{fmt.Sprintf("Bearer %s", responseAuth.Response.Token)},
-		},
+		Method:  http.MethodDelete,
+		Path:    path,
+		Headers: authorizationHeader(token),
 	}
 
-	return s.client.executeQuery(options)
+	return s.client.executeQuery(ctx, options)
+}
 
+func (s *recordService) GetById(recordId string) (*ResponseData, error) {
+	if err := validateRecordID(recordId); err != nil {
+		return nil, err
+	}
+	if err := validatePortals(s.portals); err != nil {
+		return nil, err
+	}
+
+	path := s.recordsPath(recordId)
+	params := url.Values{}
+	addPortalParams(params, s.portals)
+	params = withExtraParams(params, s.params)
+	return s.client.coalesce(s.readKey(path, params), func() (*ResponseData, error) {
+		ctx := s.client.operationContext(context.Background())
+		responseAuth, err := s.client.connect(ctx, s.database)
+		if err != nil {
+			return nil, err
+		}
+
+		defer s.client.disconnect(ctx, s.database, responseAuth.Response.Token)
+
+		options := &performRequestOptions{
+			Method:  http.MethodGet,
+			Path:    path,
+			Params:  params,
+			Headers: authorizationHeader(responseAuth.Response.Token),
+		}
+
+		return s.filterFields(s.client.executeQuery(ctx, options))
+	})
 }
 
+// List returns the records of the layout from offset, 1-based, up to limit,
+// at least 1, sorted by sorters. An empty offset or limit is left to the
+// server.
 func (s *recordService) List(offset, limit string, sorters ...*Sorter) (*ResponseData, error) {
-	responseAuth, err := s.client.Connect(s.database)
-	if err != nil {
+	if err := validatePaging(offset, limit); err != nil {
 		return nil, err
 	}
+	if err := validateSorters(sorters); err != nil {
+		return nil, err
+	}
+	if err := validatePortals(s.portals); err != nil {
+		return nil, err
+	}
+
+	params := s.listParams(offset, limit, sorters...)
+	return s.client.coalesce(s.readKey(s.recordsPath(), params), func() (*ResponseData, error) {
+		ctx := s.client.operationContext(context.Background())
+		responseAuth, err := s.client.connect(ctx, s.database)
+		if err != nil {
+			return nil, err
+		}
+
+		defer s.client.disconnect(ctx, s.database, responseAuth.Response.Token)
 
-	defer s.client.Disconnect(s.database, responseAuth.Response.Token)
+		return s.list(ctx, responseAuth.Response.Token, offset, limit, sorters...)
+	})
+}
 
-	path := fmt.Sprintf(recordsPath, s.client.version, s.database, s.layout)
+func (s *recordService) list(ctx context.Context, token, offset, limit string, sorters ...*Sorter) (*ResponseData, error) {
+	options := &performRequestOptions{
+		Method:  http.MethodGet,
+		Path:    s.recordsPath(),
+		Params:  s.listParams(offset, limit, sorters...),
+		Headers: authorizationHeader(token),
+	}
+	return s.filterFields(s.client.executeQuery(ctx, options))
+}
 
+func (s *recordService) listParams(offset, limit string, sorters ...*Sorter) url.Values {
 	params := url.Values{}
 	params.Add("_offset", offset)
 	params.Add("_limit", limit)
@@ -166,16 +586,58 @@ func (s *recordService) List(offset, limit string, sorters ...*Sorter) (*Respons
 	if sortersStr != "" {
 		params.Add("_sort", sortersStr)
 	}
+	addPortalParams(params, s.portals)
+	return withExtraParams(params, s.params)
+}
 
-	options := &performRequestOptions{
-		Method: http.MethodGet,
-		Path:   path,
-		Params: params,
-		Headers: http.Header{
-			"Authorization": []string{fmt.Sprintf("Bearer %s", responseAuth.Response.Token)},
-		},
+// withExtraParams adds the extra parameters whose key isn't set in params.
+// It returns nil when both are empty.
+func withExtraParams(params, extra url.Values) url.Values {
+	if len(extra) == 0 {
+		return params
+	}
+	if params == nil {
+		params = url.Values{}
 	}
-	return s.client.executeQuery(options)
+	for key, values := range extra {
+		if _, ok := params[key]; !ok {
+			params[key] = append([]string(nil), values...)
+		}
+	}
+	return params
+}
+
+func copyParams(params url.Values) url.Values {
+	if params == nil {
+		return nil
+	}
+	return withExtraParams(url.Values{}, params)
+}
+
+// readKey identifies a GET for request coalescing. The field selection is
+// part of the key because it changes the returned records.
+func (s *recordService) readKey(path string, params url.Values) string {
+	return http.MethodGet + " " + path + "?" + params.Encode() + " " + strings.Join(s.fields, ",")
+}
+
+func (s *recordService) filterFields(response *ResponseData, err error) (*ResponseData, error) {
+	if err != nil || response == nil || len(s.fields) == 0 {
+		return response, err
+	}
+	for i := range response.Response.Data {
+		fieldData, ok := response.Response.Data[i].FieldData.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		filtered := make(map[string]interface{}, len(s.fields))
+		for _, name := range s.fields {
+			if value, ok := fieldData[name]; ok {
+				filtered[name] = value
+			}
+		}
+		response.Response.Data[i].FieldData = filtered
+	}
+	return response, nil
 }
 
 func sortersToJson(sorters ...*Sorter) string {
@@ -188,3 +650,53 @@ func sortersToJson(sorters ...*Sorter) string {
 	}
 	return ""
 }
+
+func (s *recordService) recordsPath(segments ...string) string {
+	return s.client.dataPath(append([]string{"databases", s.database, "layouts", s.layout, "records"}, segments...)...)
+}
+
+// RecordURL returns the Data API URL of recordId on the layout of database,
+// e.g. to publish the record created by Create.
+func (c *Client) RecordURL(database, layout, recordId string) string {
+	return c.url + "/" + c.dataPath("databases", database, "layouts", layout, "records", recordId)
+}
+
+func authorizationHeader(token string) http.Header {
+	return http.Header{
+		"Authorization": []string{fmt.Sprintf("Bearer %s", token)},
+	}
+}
+
+// fieldDataMap returns the payload field data as a generic map.
+func fieldDataMap(fieldData interface{}) (map[string]interface{}, error) {
+	switch data := fieldData.(type) {
+	case map[string]interface{}:
+		return data, nil
+	case map[string]string:
+		result := make(map[string]interface{}, len(data))
+		for name, value := range data {
+			result[name] = value
+		}
+		return result, nil
+	case *OrderedFields:
+		return data.values, nil
+	default:
+		return nil, fmt.Errorf("filemaker: unsupported field data type %T", fieldData)
+	}
+}
+
+// fieldValue returns the value of field in the payload field data as a string.
+func fieldValue(payload *Payload, field string) (string, error) {
+	if payload == nil {
+		return "", errors.New("Empty payload")
+	}
+	fieldData, err := fieldDataMap(payload.FieldData)
+	if err != nil {
+		return "", err
+	}
+	value, ok := fieldData[field]
+	if !ok || value == nil {
+		return "", fmt.Errorf("filemaker: field %s not found in payload", field)
+	}
+	return fmt.Sprint(value), nil
+}
@@ -0,0 +1,521 @@
+package filemaker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_recordService_CreateIdempotent(t *testing.T) {
+	newServer := func(existing bool, creates *int) *fakeServer {
+		return newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case strings.HasSuffix(r.URL.Path, "/_find"):
+				body, _ := ioutil.ReadAll(r.Body)
+				if !strings.Contains(string(body), `"code":"==A-1"`) {
+					t.Errorf("Find body was incorrect, got: %s", string(body))
+				}
+				if existing {
+					w.Write([]byte(`{"response":{"data":[{"fieldData":{"code":"A-1"},"recordId":"7","modId":"3"}]},"messages":[{"code":"0","message":"OK"}]}`))
+					return
+				}
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"response":{},"messages":[{"code":"401","message":"No records match the request"}]}`))
+			case strings.HasSuffix(r.URL.Path, "/records") && r.Method == http.MethodPost:
+				*creates++
+				w.Write([]byte(`{"response":{"recordId":"8","modId":"0"},"messages":[{"code":"0","message":"OK"}]}`))
+			default:
+				t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			}
+		})
+	}
+	payload := &Payload{FieldData: map[string]interface{}{"code": "A-1"}}
+
+	t.Run("Test returns existing record", func(t *testing.T) {
+		creates := 0
+		server := newServer(true, &creates)
+		service := NewRecordService("test", "test_layout", newTestClient(t, server.URL))
+		resp, err := service.CreateIdempotent("code", payload)
+		if err != nil {
+			t.Fatalf("CreateIdempotent() error = %v", err)
+		}
+		if resp.Response.RecordID != "7" || creates != 0 {
+			t.Errorf("CreateIdempotent was incorrect, got: recordId %s and %d creates, want: recordId %s and %d creates", resp.Response.RecordID, creates, "7", 0)
+		}
+		if server.disconnectCount() != 1 {
+			t.Errorf("Disconnect calls was incorrect, got: %d, want: %d", server.disconnectCount(), 1)
+		}
+	})
+
+	t.Run("Test creates missing record", func(t *testing.T) {
+		creates := 0
+		server := newServer(false, &creates)
+		service := NewRecordService("test", "test_layout", newTestClient(t, server.URL))
+		resp, err := service.CreateIdempotent("code", payload)
+		if err != nil {
+			t.Fatalf("CreateIdempotent() error = %v", err)
+		}
+		if resp.Response.RecordID != "8" || creates != 1 {
+			t.Errorf("CreateIdempotent was incorrect, got: recordId %s and %d creates, want: recordId %s and %d creates", resp.Response.RecordID, creates, "8", 1)
+		}
+	})
+
+	t.Run("Test lookup error does not create", func(t *testing.T) {
+		creates := 0
+		server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/records") {
+				creates++
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"response":{},"messages":[{"code":"102","message":"Field is missing"}]}`))
+		})
+		service := NewRecordService("test", "test_layout", newTestClient(t, server.URL))
+		_, err := service.CreateIdempotent("code", payload)
+		var fmErr *FileMakerError
+		if !errors.As(err, &fmErr) || fmErr.Code != "102" || creates != 0 {
+			t.Errorf("CreateIdempotent was incorrect, got: %v and %d creates, want: error 102 and no create", err, creates)
+		}
+	})
+
+	t.Run("Test value with find operators", func(t *testing.T) {
+		var body string
+		server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+			data, _ := ioutil.ReadAll(r.Body)
+			if strings.HasSuffix(r.URL.Path, "/_find") {
+				body = string(data)
+			}
+			w.Write([]byte(`{"response":{"recordId":"9","modId":"0"},"messages":[{"code":"401","message":"No records match the request"}]}`))
+		})
+		service := NewRecordService("test", "test_layout", newTestClient(t, server.URL))
+		if _, err := service.CreateIdempotent("code", &Payload{FieldData: map[string]interface{}{"code": "a*@x.com"}}); err != nil {
+			t.Fatalf("CreateIdempotent() error = %v", err)
+		}
+		if !strings.Contains(body, `"code":"==a\\*\\@x.com"`) {
+			t.Errorf("Find body was incorrect, got: %s, want the operators escaped", body)
+		}
+	})
+
+	t.Run("Test missing unique field", func(t *testing.T) {
+		service := NewRecordService("test", "test_layout", nil)
+		if _, err := service.CreateIdempotent("missing", payload); err == nil {
+			t.Errorf("CreateIdempotent() without the unique field should fail")
+		}
+	})
+}
+
+func Test_recordService_Fields(t *testing.T) {
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"response":{"data":[{"fieldData":{"name":"pablo","last_name":"zenteno","notes":"long"},"recordId":"1","modId":"0"}]},"messages":[{"code":"0","message":"OK"}]}`))
+	})
+	service := NewRecordService("test", "test_layout", newTestClient(t, server.URL)).Fields("name", "missing")
+
+	resp, err := service.GetById("1")
+	if err != nil {
+		t.Fatalf("GetById() error = %v", err)
+	}
+	fieldData := resp.Response.Data[0].FieldData.(map[string]interface{})
+	if len(fieldData) != 1 || fieldData["name"] != "pablo" {
+		t.Errorf("FieldData was incorrect, got: %v, want: %v", fieldData, map[string]interface{}{"name": "pablo"})
+	}
+}
+
+func Test_recordService_EditChanged(t *testing.T) {
+	var bodies []string
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		w.Write([]byte(`{"response":{"modId":"2"},"messages":[{"code":"0","message":"OK"}]}`))
+	})
+	service := NewRecordService("test", "test_layout", newTestClient(t, server.URL))
+	original := map[string]interface{}{"name": "pablo", "age": "30", "city": "Santiago"}
+
+	t.Run("Test sends only changed fields", func(t *testing.T) {
+		bodies = nil
+		_, err := service.EditChanged("1", original, &Payload{FieldData: map[string]interface{}{"name": "pablo", "age": 31, "country": "CL"}})
+		if err != nil {
+			t.Fatalf("EditChanged() error = %v", err)
+		}
+		want := "{\"fieldData\":{\"age\":31,\"country\":\"CL\"}}"
+		if len(bodies) != 1 || bodies[0] != want {
+			t.Errorf("Edit body was incorrect, got: %v, want: %s", bodies, want)
+		}
+	})
+
+	t.Run("Test nothing changed", func(t *testing.T) {
+		bodies = nil
+		resp, err := service.EditChanged("1", original, &Payload{FieldData: map[string]string{"age": "30"}})
+		if err != nil || len(bodies) != 0 {
+			t.Fatalf("EditChanged() without changes should not send a request, got: %v after %d requests", err, len(bodies))
+		}
+		if resp == nil || resp.Err() != nil || resp.Response.RecordID != "1" {
+			t.Errorf("Response was incorrect, got: %+v, want: a success for record %s", resp, "1")
+		}
+	})
+}
+
+func Test_recordService_List(t *testing.T) {
+	t.Run("Test list with dataInfo", func(t *testing.T) {
+		server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"response":{"dataInfo":{"database":"test","layout":"test_layout","table":"test","totalRecordCount":5000,"foundCount":5000,"returnedCount":1},"data":[{"fieldData":{},"recordId":"1","modId":"0"}]},"messages":[{"code":"0","message":"OK"}]}`))
+		})
+		resp, err := NewRecordService("test", "test_layout", newTestClient(t, server.URL)).List("1", "1")
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		if resp.TotalRecordCount() != 5000 {
+			t.Errorf("TotalRecordCount was incorrect, got: %d, want: %d", resp.TotalRecordCount(), 5000)
+		}
+	})
+
+	t.Run("Test list without dataInfo", func(t *testing.T) {
+		server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"response":{"data":[]},"messages":[{"code":"0","message":"OK"}]}`))
+		})
+		resp, err := NewRecordService("test", "test_layout", newTestClient(t, server.URL)).List("1", "1")
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		if resp.TotalRecordCount() != 0 {
+			t.Errorf("TotalRecordCount was incorrect, got: %d, want: %d", resp.TotalRecordCount(), 0)
+		}
+	})
+}
+
+func Test_recordService_DeleteByQuery(t *testing.T) {
+	var deletes []string
+	var authorization string
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/_find"):
+			w.Write([]byte(`{"response":{"data":[{"fieldData":{},"recordId":"1","modId":"0"},{"fieldData":{},"recordId":"2","modId":"0"},{"fieldData":{},"recordId":"3","modId":"0"}]},"messages":[{"code":"0","message":"OK"}]}`))
+		case r.Method == http.MethodDelete:
+			recordId := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+			deletes = append(deletes, recordId)
+			if recordId == "2" {
+				w.Write([]byte(`{"response":{},"messages":[{"code":"301","message":"Record is in use by another user"}]}`))
+				return
+			}
+			w.Write([]byte(`{"response":{},"messages":[{"code":"0","message":"OK"}]}`))
+		}
+	})
+	service := NewRecordService("test", "test_layout", newTestClient(t, server.URL))
+	queryGroups := []*groupQuery{NewGroupQuery(NewQueryFieldOperator("status", "old", Equal))}
+
+	t.Run("Test own session", func(t *testing.T) {
+		deletes = nil
+		deleted, err := service.DeleteByQuery(context.Background(), queryGroups, "")
+		batchErr, ok := err.(*BatchError)
+		if !ok || len(batchErr.Errors) != 1 || batchErr.Errors["2"] == nil {
+			t.Fatalf("Error was incorrect, got: %v, want: %T for record 2", err, &BatchError{})
+		}
+		if strings.Join(deleted, ",") != "1,3" || len(deletes) != 3 {
+			t.Errorf("Deleted was incorrect, got: %v, want: %v", deleted, []string{"1", "3"})
+		}
+		if server.disconnectCount() != 1 {
+			t.Errorf("Disconnect calls was incorrect, got: %d, want: %d", server.disconnectCount(), 1)
+		}
+	})
+
+	t.Run("Test caller session", func(t *testing.T) {
+		deletes = nil
+		disconnects := server.disconnectCount()
+		deleted, _ := service.DeleteByQuery(context.Background(), queryGroups, "caller-token")
+		if strings.Join(deleted, ",") != "1,3" || len(deletes) != 3 {
+			t.Errorf("Deleted was incorrect, got: %v, want: %v", deleted, []string{"1", "3"})
+		}
+		if authorization != "Bearer caller-token" || server.disconnectCount() != disconnects {
+			t.Errorf("Session was incorrect, got: %q and %d disconnects, want: the caller token kept open", authorization, server.disconnectCount()-disconnects)
+		}
+	})
+}
+
+func Test_recordService_EditDatum(t *testing.T) {
+	var body, path string
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		body, path = string(data), r.URL.Path
+		w.Write([]byte(`{"response":{"modId":"5"},"messages":[{"code":"0","message":"OK"}]}`))
+	})
+	service := NewRecordService("test", "test_layout", newTestClient(t, server.URL))
+
+	datum := &Datum{RecordID: "12", ModID: "4"}
+	if _, err := service.EditDatum(datum, &Payload{FieldData: map[string]string{"name": "pablo"}}); err != nil {
+		t.Fatalf("EditDatum() error = %v", err)
+	}
+	want := "{\"fieldData\":{\"name\":\"pablo\"},\"modId\":\"4\"}"
+	if body != want || !strings.HasSuffix(path, "/records/12") {
+		t.Errorf("Edit request was incorrect, got: %s %s, want: %s", path, body, want)
+	}
+}
+
+func TestClient_RecordURL(t *testing.T) {
+	client := newTestClient(t, "https://fm.example.com")
+	got := client.RecordURL("Sales", "Orders", "42")
+	want := "https://fm.example.com/fmi/data/vLatest/databases/Sales/layouts/Orders/records/42"
+	if got != want {
+		t.Errorf("RecordURL was incorrect, got: %s, want: %s", got, want)
+	}
+}
+
+func Test_recordService_ImportTransactional(t *testing.T) {
+	newServer := func(t *testing.T, failAt int, deleted *[]string) *fakeServer {
+		var created int
+		return newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodPost:
+				created++
+				if created == failAt {
+					w.Write([]byte(`{"response":{},"messages":[{"code":"504","message":"Value in field is not unique"}]}`))
+					return
+				}
+				fmt.Fprintf(w, `{"response":{"recordId":"%d","modId":"0"},"messages":[{"code":"0","message":"OK"}]}`, created)
+			case http.MethodDelete:
+				*deleted = append(*deleted, r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:])
+				w.Write([]byte(`{"response":{},"messages":[{"code":"0","message":"OK"}]}`))
+			}
+		})
+	}
+	payloads := []*Payload{
+		{FieldData: map[string]string{"name": "a"}},
+		{FieldData: map[string]string{"name": "b"}},
+		{FieldData: map[string]string{"name": "c"}},
+	}
+
+	t.Run("Test all created", func(t *testing.T) {
+		var deleted []string
+		server := newServer(t, 0, &deleted)
+		ids, err := NewRecordService("test", "test_layout", newTestClient(t, server.URL)).ImportTransactional(context.Background(), payloads)
+		if err != nil {
+			t.Fatalf("ImportTransactional() error = %v", err)
+		}
+		if strings.Join(ids, ",") != "1,2,3" || len(deleted) != 0 {
+			t.Errorf("ImportTransactional() was incorrect, got: %v deleted %v, want: [1 2 3] deleted []", ids, deleted)
+		}
+	})
+
+	t.Run("Test rollback on failure", func(t *testing.T) {
+		var deleted []string
+		server := newServer(t, 3, &deleted)
+		ids, err := NewRecordService("test", "test_layout", newTestClient(t, server.URL)).ImportTransactional(context.Background(), payloads)
+		importErr, ok := err.(*ImportError)
+		if !ok {
+			t.Fatalf("ImportTransactional() error type was incorrect, got: %T, want: %T", err, &ImportError{})
+		}
+		if importErr.Index != 2 || importErr.Rollback != nil || ids != nil {
+			t.Errorf("ImportError was incorrect, got: %+v, ids: %v", importErr, ids)
+		}
+		var fmErr *FileMakerError
+		if !errors.As(err, &fmErr) || fmErr.Code != "504" {
+			t.Errorf("Unwrapped FileMakerError was incorrect, got: %v", fmErr)
+		}
+		if strings.Join(deleted, ",") != "1,2" {
+			t.Errorf("Deleted records were incorrect, got: %v, want: %v", deleted, []string{"1", "2"})
+		}
+	})
+
+	t.Run("Test rollback on cancel", func(t *testing.T) {
+		var deleted []string
+		server := newServer(t, 0, &deleted)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var creates int
+		// The doer cancels ctx once the second record is created, after
+		// buffering its response so the create itself succeeds.
+		doer := doerFunc(func(req *http.Request) (*http.Response, error) {
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil || req.Method != http.MethodPost || strings.HasSuffix(req.URL.Path, "/sessions") {
+				return resp, err
+			}
+			body, err := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+			if creates++; creates == 2 {
+				cancel()
+			}
+			return resp, err
+		})
+		_, err := NewRecordService("test", "test_layout", newTestClient(t, server.URL, SetDoer(doer))).ImportTransactional(ctx, payloads)
+		importErr, ok := err.(*ImportError)
+		if !ok || !errors.Is(err, context.Canceled) || importErr.Index != 2 || importErr.Rollback != nil {
+			t.Fatalf("ImportTransactional() error was incorrect, got: %v, want: %T wrapping %v", err, &ImportError{}, context.Canceled)
+		}
+		if strings.Join(deleted, ",") != "1,2" {
+			t.Errorf("Deleted records were incorrect, got: %v, want: %v", deleted, []string{"1", "2"})
+		}
+		if server.disconnectCount() != 1 {
+			t.Errorf("Disconnect calls was incorrect, got: %d, want: %d", server.disconnectCount(), 1)
+		}
+	})
+}
+
+func Test_recordService_RetryOnLock(t *testing.T) {
+	newServer := func(t *testing.T, lockedTimes int, edits *int) *fakeServer {
+		return newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+			*edits++
+			if *edits <= lockedTimes {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"response":{},"messages":[{"code":"301","message":"Record is in use by another user"}]}`))
+				return
+			}
+			w.Write([]byte(`{"response":{"modId":"2"},"messages":[{"code":"0","message":"OK"}]}`))
+		})
+	}
+	payload := &Payload{FieldData: map[string]string{"name": "a"}}
+
+	t.Run("Test retried until unlocked", func(t *testing.T) {
+		var edits int
+		server := newServer(t, 2, &edits)
+		resp, err := NewRecordService("test", "test_layout", newTestClient(t, server.URL)).
+			RetryOnLock(3, time.Millisecond).
+			Edit("1", payload)
+		if err != nil || resp.Err() != nil || edits != 3 {
+			t.Errorf("Edit() was incorrect, got: %v, %v after %d edits, want success after 3", err, resp.Err(), edits)
+		}
+	})
+
+	t.Run("Test not retried by default", func(t *testing.T) {
+		var edits int
+		server := newServer(t, 1, &edits)
+		resp, err := NewRecordService("test", "test_layout", newTestClient(t, server.URL)).Edit("1", payload)
+		if err != nil || !IsLockError(resp.Err()) || edits != 1 {
+			t.Errorf("Edit() was incorrect, got: %v, %v after %d edits, want a lock error after 1", err, resp.Err(), edits)
+		}
+	})
+
+	t.Run("Test wait ends with the context", func(t *testing.T) {
+		var edits int
+		server := newServer(t, 3, &edits)
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		_, err := NewRecordService("test", "test_layout", newTestClient(t, server.URL)).
+			RetryOnLock(3, time.Minute).
+			edit(ctx, "test-token", "1", payload)
+		if !errors.Is(err, context.DeadlineExceeded) || edits != 1 || time.Since(start) > 10*time.Second {
+			t.Errorf("edit() was incorrect, got: %v after %d edits in %v, want: %v after 1", err, edits, time.Since(start), context.DeadlineExceeded)
+		}
+	})
+}
+
+func Test_recordService_SetParam(t *testing.T) {
+	var query map[string][]string
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{"response":{"data":[]},"messages":[{"code":"0","message":"OK"}]}`))
+	})
+	service := NewRecordService("test", "test_layout", newTestClient(t, server.URL)).
+		SetParam("_future", "on").
+		SetParam("_limit", "999")
+
+	if _, err := service.List("1", "10"); err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if got := query["_future"]; len(got) != 1 || got[0] != "on" {
+		t.Errorf("_future was incorrect, got: %v, want: %v", got, "on")
+	}
+	if got := query["_limit"]; len(got) != 1 || got[0] != "10" {
+		t.Errorf("_limit was incorrect, got: %v, want: %v", got, "10")
+	}
+
+	if _, err := service.GetById("1"); err != nil {
+		t.Fatalf("GetById() error = %v", err)
+	}
+	if got := query["_future"]; len(got) != 1 || got[0] != "on" {
+		t.Errorf("_future was incorrect, got: %v, want: %v", got, "on")
+	}
+}
+
+func Test_recordService_RecordIDValidation(t *testing.T) {
+	service := NewRecordService("test", "test_layout", nil)
+	payload := &Payload{FieldData: map[string]string{"name": "a"}}
+	tests := []struct {
+		name string
+		call func() (*ResponseData, error)
+	}{
+		{name: "GetById", call: func() (*ResponseData, error) { return service.GetById("ORD-1") }},
+		{name: "Edit", call: func() (*ResponseData, error) { return service.Edit("ORD-1", payload) }},
+		{name: "Edit modId", call: func() (*ResponseData, error) {
+			return service.Edit("1", &Payload{FieldData: payload.FieldData, ModID: "v2"})
+		}},
+		{name: "Delete", call: func() (*ResponseData, error) { return service.Delete("") }},
+		{name: "Duplicate", call: func() (*ResponseData, error) { return service.Duplicate("1a") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.call(); err == nil {
+				t.Errorf("%s() with a non-numeric ID should fail", tt.name)
+			} else if _, ok := err.(*ValidationError); !ok {
+				t.Errorf("%s() error type was incorrect, got: %T, want: %T", tt.name, err, &ValidationError{})
+			}
+		})
+	}
+}
+
+func Test_recordService_CreateRecord(t *testing.T) {
+	t.Run("Test created", func(t *testing.T) {
+		server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"response":{"recordId":"42","modId":"0"},"messages":[{"code":"0","message":"OK"}]}`))
+		})
+		result, err := NewRecordService("test", "test_layout", newTestClient(t, server.URL)).
+			CreateRecord(&Payload{FieldData: map[string]string{"name": "a"}})
+		if err != nil {
+			t.Fatalf("CreateRecord() error = %v", err)
+		}
+		if result.RecordID != "42" || result.ModID != "0" {
+			t.Errorf("CreateRecord() was incorrect, got: %+v, want: %+v", result, &CreateResult{RecordID: "42", ModID: "0"})
+		}
+	})
+
+	t.Run("Test FileMaker error", func(t *testing.T) {
+		server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"response":{},"messages":[{"code":"504","message":"Value in field is not unique"}]}`))
+		})
+		_, err := NewRecordService("test", "test_layout", newTestClient(t, server.URL)).
+			CreateRecord(&Payload{FieldData: map[string]string{"name": "a"}})
+		if _, ok := err.(*FileMakerError); !ok {
+			t.Errorf("CreateRecord() error type was incorrect, got: %T, want: %T", err, &FileMakerError{})
+		}
+	})
+}
+
+func Test_recordService_ListPaging(t *testing.T) {
+	tests := []struct {
+		name          string
+		offset, limit string
+	}{
+		{name: "zero offset", offset: "0", limit: "10"},
+		{name: "zero limit", offset: "1", limit: "0"},
+		{name: "not a number", offset: "first", limit: "10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRecordService("test", "test_layout", nil).List(tt.offset, tt.limit)
+			if _, ok := err.(*ValidationError); !ok {
+				t.Errorf("List() error was incorrect, got: %v, want: %T", err, &ValidationError{})
+			}
+		})
+	}
+}
+
+func Test_recordService_Clone(t *testing.T) {
+	base := NewRecordService("test", "test_layout", nil).
+		Portals(NewPortalConfig("items").WithLimit(10))
+	want := base.portals[0].ToQueryParams().Encode()
+
+	clone := base.Clone()
+	clone.portals[0].WithLimit(5).WithOffset(20)
+
+	if got := base.portals[0].ToQueryParams().Encode(); got != want {
+		t.Errorf("Base portal was modified, got: %s, want: %s", got, want)
+	}
+	if got := clone.portals[0].ToQueryParams().Get("_offset.items"); got != "20" {
+		t.Errorf("Clone portal offset was incorrect, got: %s, want: %s", got, "20")
+	}
+}
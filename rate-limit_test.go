@@ -0,0 +1,41 @@
+package filemaker
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func Test_rateLimiter_wait(t *testing.T) {
+	t.Run("Test burst then throttle", func(t *testing.T) {
+		limiter := newRateLimiter(20, 2)
+		start := time.Now()
+		for i := 0; i < 4; i++ {
+			if err := limiter.wait(context.Background()); err != nil {
+				t.Fatalf("wait() error = %v", err)
+			}
+		}
+		if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+			t.Errorf("wait() was incorrect, got: %v for 4 requests, want: at least %v", elapsed, 80*time.Millisecond)
+		}
+	})
+
+	t.Run("Test context done", func(t *testing.T) {
+		limiter := newRateLimiter(0.1, 1)
+		limiter.wait(context.Background())
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if err := limiter.wait(ctx); err != context.DeadlineExceeded {
+			t.Errorf("wait() error was incorrect, got: %v, want: %v", err, context.DeadlineExceeded)
+		}
+	})
+
+	t.Run("Test invalid options", func(t *testing.T) {
+		if _, err := NewClient(SetRateLimit(0, 1)); err == nil {
+			t.Errorf("NewClient() with a zero rate should fail")
+		}
+		if _, err := NewClient(SetRateLimit(1, 0)); err == nil {
+			t.Errorf("NewClient() with a zero burst should fail")
+		}
+	})
+}
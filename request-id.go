@@ -0,0 +1,49 @@
+package filemaker
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying id, sent instead of a
+// generated id in the header set with SetRequestIDHeader. Only the methods
+// taking a context.Context honour it; the others generate an id of their own,
+// shared by every request of the operation, session and logout included.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+func requestID(ctx context.Context) string {
+	if id, ok := ctx.Value(requestIDKey{}).(string); ok && id != "" {
+		return id
+	}
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(buf)
+}
+
+// operationContext returns ctx carrying the request id shared by every
+// request of one operation, generating it when ctx has none.
+func (c *Client) operationContext(ctx context.Context) context.Context {
+	if c.requestIDHeader == "" {
+		return ctx
+	}
+	if id, ok := ctx.Value(requestIDKey{}).(string); ok && id != "" {
+		return ctx
+	}
+	return ContextWithRequestID(ctx, requestID(ctx))
+}
+
+// detachRequestID returns a background context carrying the request id of
+// ctx, if any.
+func detachRequestID(ctx context.Context) context.Context {
+	if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+		return ContextWithRequestID(context.Background(), id)
+	}
+	return context.Background()
+}
@@ -0,0 +1,119 @@
+package filemaker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type scriptService struct {
+	database string
+	layout   string
+	client   *Client
+}
+
+// NewScriptService runs scripts through the Data API script endpoint. The
+// layout only sets the context the script starts on; no record is created,
+// edited or otherwise touched to run it.
+func NewScriptService(database, layout string, client *Client) *scriptService {
+	return &scriptService{
+		database: database,
+		layout:   layout,
+		client:   client,
+	}
+}
+
+// Execute runs script with param and returns its result and error code in
+// Response.ScriptResult and Response.ScriptError.
+func (s *scriptService) Execute(script, param string) (*ResponseData, error) {
+	if err := validateScriptName(script); err != nil {
+		return nil, err
+	}
+	ctx := s.client.operationContext(context.Background())
+	responseAuth, err := s.client.connect(ctx, s.database)
+	if err != nil {
+		return nil, err
+	}
+
+	defer s.client.disconnect(ctx, s.database, responseAuth.Response.Token)
+
+	return s.execute(ctx, responseAuth.Response.Token, script, param)
+}
+
+// ExecuteWithTimeout is like Execute but gives the script call its own
+// deadline, returning a *TimeoutError when it expires. Session creation and
+// cleanup are not counted against timeout.
+//
+// The deadline only applies client side: FileMaker Server keeps running the
+// script until it ends or hits the server's own script timeout. It also can't
+// extend the Timeout of an *http.Client given to SetHttpClient, which bounds
+// every request.
+func (s *scriptService) ExecuteWithTimeout(script, param string, timeout time.Duration) (*ResponseData, error) {
+	if err := validateScriptName(script); err != nil {
+		return nil, err
+	}
+	ctx := s.client.operationContext(context.Background())
+	responseAuth, err := s.client.connect(ctx, s.database)
+	if err != nil {
+		return nil, err
+	}
+
+	defer s.client.disconnect(ctx, s.database, responseAuth.Response.Token)
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	resp, err := s.execute(ctx, responseAuth.Response.Token, script, param)
+	if err != nil && errors.Is(err, context.DeadlineExceeded) {
+		return nil, &TimeoutError{Operation: "script " + script, Timeout: timeout}
+	}
+	return resp, err
+}
+
+// Validate runs a validation script without creating, editing or finding any
+// record and returns its result. A non-zero script error is returned as a
+// *ScriptStageError carrying the result.
+//
+// The Data API only runs a pre-request script as part of a record operation,
+// so reuse the script as is here instead: the script endpoint runs it alone,
+// with the same parameter, making it the side-effect free target.
+func (s *scriptService) Validate(script, param string) (string, error) {
+	resp, err := s.Execute(script, param)
+	if err != nil {
+		return "", err
+	}
+	if err := resp.Err(); err != nil {
+		return "", err
+	}
+	result := resp.Response.ScriptResult
+	if code := resp.Response.ScriptError; code != "" && code != "0" {
+		return result, &ScriptStageError{Stage: MainStage, Code: code, Result: result}
+	}
+	return result, nil
+}
+
+func validateScriptName(script string) error {
+	if script == "" {
+		return &ValidationError{Field: "script", Message: "empty script name"}
+	}
+	return nil
+}
+
+func (s *scriptService) execute(ctx context.Context, token, script, param string) (*ResponseData, error) {
+	path := s.client.dataPath("databases", s.database, "layouts", s.layout, "script", script)
+
+	params := url.Values{}
+	if param != "" {
+		params.Add("script.param", param)
+	}
+
+	options := &performRequestOptions{
+		Method:  http.MethodGet,
+		Path:    path,
+		Params:  params,
+		Headers: authorizationHeader(token),
+	}
+	return s.client.executeQuery(ctx, options)
+}
@@ -0,0 +1,126 @@
+package filemaker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_scriptService_Execute(t *testing.T) {
+	var requests []string
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.EscapedPath()+"?"+r.URL.RawQuery)
+		w.Write([]byte(`{"response":{"scriptResult":"done","scriptError":"0"},"messages":[{"code":"0","message":"OK"}]}`))
+	})
+	service := NewScriptService("test", "test_layout", newTestClient(t, server.URL))
+
+	resp, err := service.Execute("Make Report", "2020")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if resp.Response.ScriptResult != "done" || resp.Response.ScriptError != "0" {
+		t.Errorf("Script result was incorrect, got: %+v", resp.Response)
+	}
+
+	want := "GET /fmi/data/vLatest/databases/test/layouts/test_layout/script/Make%20Report?script.param=2020"
+	if len(requests) != 1 || requests[0] != want {
+		t.Errorf("Requests were incorrect, got: %v, want only: %s", requests, want)
+	}
+}
+
+func Test_scriptService_EmptyScriptName(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	t.Cleanup(server.Close)
+	service := NewScriptService("test", "test_layout", newTestClient(t, server.URL))
+
+	_, err := service.Execute("", "")
+	if _, ok := err.(*ValidationError); !ok || requests != 0 {
+		t.Errorf("Execute() was incorrect, got: %v after %d requests, want: %T before any request", err, requests, &ValidationError{})
+	}
+	_, err = service.ExecuteWithTimeout("", "", time.Second)
+	if _, ok := err.(*ValidationError); !ok || requests != 0 {
+		t.Errorf("ExecuteWithTimeout() was incorrect, got: %v after %d requests, want: %T before any request", err, requests, &ValidationError{})
+	}
+}
+
+func Test_scriptService_ExecuteCreatesNoRecord(t *testing.T) {
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/records") {
+			t.Errorf("Running a script should not touch records, got: %s %s", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(`{"response":{"scriptError":"0"},"messages":[{"code":"0","message":"OK"}]}`))
+	})
+	service := NewScriptService("test", "test_layout", newTestClient(t, server.URL))
+
+	if _, err := service.Execute("After Import", ""); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+}
+
+func Test_scriptService_ExecuteWithTimeout(t *testing.T) {
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/Slow") {
+			time.Sleep(200 * time.Millisecond)
+		}
+		if strings.HasSuffix(r.URL.Path, "/SlowBody") {
+			w.Write([]byte(`{"response":`))
+			w.(http.Flusher).Flush()
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.Write([]byte(`{"response":{"scriptError":"0"},"messages":[{"code":"0","message":"OK"}]}`))
+	})
+	service := NewScriptService("test", "test_layout", newTestClient(t, server.URL))
+
+	t.Run("Test script over the deadline", func(t *testing.T) {
+		_, err := service.ExecuteWithTimeout("Slow", "", 50*time.Millisecond)
+		if _, ok := err.(*TimeoutError); !ok {
+			t.Errorf("Error was incorrect, got: %v, want: %T", err, &TimeoutError{})
+		}
+	})
+
+	t.Run("Test body over the deadline", func(t *testing.T) {
+		_, err := service.ExecuteWithTimeout("SlowBody", "", 50*time.Millisecond)
+		if _, ok := err.(*TimeoutError); !ok {
+			t.Errorf("Error was incorrect, got: %v, want: %T", err, &TimeoutError{})
+		}
+	})
+
+	t.Run("Test script within the deadline", func(t *testing.T) {
+		if _, err := service.ExecuteWithTimeout("Fast", "", time.Second); err != nil {
+			t.Errorf("ExecuteWithTimeout() error = %v", err)
+		}
+	})
+}
+
+func Test_scriptService_Validate(t *testing.T) {
+	newServer := func(t *testing.T, response string) *fakeServer {
+		return newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet || !strings.Contains(r.URL.Path, "/script/") {
+				t.Errorf("Request was incorrect, got: %s %s", r.Method, r.URL.Path)
+			}
+			w.Write([]byte(response))
+		})
+	}
+
+	t.Run("Test valid", func(t *testing.T) {
+		server := newServer(t, `{"response":{"scriptResult":"ok","scriptError":"0"},"messages":[{"code":"0","message":"OK"}]}`)
+		result, err := NewScriptService("test", "test_layout", newTestClient(t, server.URL)).Validate("ValidateOrder", `{"total":10}`)
+		if err != nil || result != "ok" {
+			t.Errorf("Validate() was incorrect, got: %q, %v, want: %q", result, err, "ok")
+		}
+	})
+
+	t.Run("Test invalid", func(t *testing.T) {
+		server := newServer(t, `{"response":{"scriptResult":"total must be positive","scriptError":"5"},"messages":[{"code":"0","message":"OK"}]}`)
+		result, err := NewScriptService("test", "test_layout", newTestClient(t, server.URL)).Validate("ValidateOrder", `{"total":-1}`)
+		scriptErr, ok := err.(*ScriptStageError)
+		if !ok || scriptErr.Stage != MainStage || scriptErr.Code != "5" || result != "total must be positive" {
+			t.Errorf("Validate() was incorrect, got: %q, %v", result, err)
+		}
+	})
+}
@@ -0,0 +1,57 @@
+package filemaker
+
+import "net/url"
+
+// ScriptContext groups the scripts FileMaker can run around a request:
+// before it (pre-request), between the find and the sort (pre-sort) and
+// after it.
+type ScriptContext struct {
+	Script          string
+	ScriptParam     string
+	PreRequest      string
+	PreRequestParam string
+	PreSort         string
+	PreSortParam    string
+}
+
+func NewScriptContext() *ScriptContext {
+	return &ScriptContext{}
+}
+
+func (sc *ScriptContext) WithScript(script, param string) *ScriptContext {
+	sc.Script = script
+	sc.ScriptParam = param
+	return sc
+}
+
+func (sc *ScriptContext) WithPreRequest(script, param string) *ScriptContext {
+	sc.PreRequest = script
+	sc.PreRequestParam = param
+	return sc
+}
+
+func (sc *ScriptContext) WithPreSort(script, param string) *ScriptContext {
+	sc.PreSort = script
+	sc.PreSortParam = param
+	return sc
+}
+
+// ToQueryParams returns the scripts as the query parameters used by GET
+// requests.
+func (sc *ScriptContext) ToQueryParams() url.Values {
+	params := url.Values{}
+	addScriptParams(params, "script", sc.Script, sc.ScriptParam)
+	addScriptParams(params, "script.prerequest", sc.PreRequest, sc.PreRequestParam)
+	addScriptParams(params, "script.presort", sc.PreSort, sc.PreSortParam)
+	return params
+}
+
+func addScriptParams(params url.Values, key, script, param string) {
+	if script == "" {
+		return
+	}
+	params.Set(key, script)
+	if param != "" {
+		params.Set(key+".param", param)
+	}
+}
@@ -1,10 +1,73 @@
 package filemaker
 
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
 type ResponseData struct {
 	Response Response  `json:"response"`
 	Messages []Message `json:"messages"`
 }
 
+// Err returns the FileMaker error reported in the response messages, or nil
+// when the request succeeded. Services return responses with an error code
+// as-is, so callers that want a Go error use Err:
+//
+//	if err := resp.Err(); err != nil && !filemaker.IsNoRecordsError(err) {
+//		return err
+//	}
+func (r *ResponseData) Err() error {
+	if r == nil || len(r.Messages) == 0 || r.Messages[0].Code == "0" {
+		return nil
+	}
+	return newFileMakerError(append([]Message(nil), r.Messages...))
+}
+
+// excludeFields removes names from the fieldData of every record.
+func (r *ResponseData) excludeFields(names []string) {
+	if r == nil || len(names) == 0 {
+		return
+	}
+	for i := range r.Response.Data {
+		fieldData, ok := r.Response.Data[i].FieldData.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, name := range names {
+			delete(fieldData, name)
+		}
+	}
+}
+
+// IsSuccess reports whether the first message of the response has code "0",
+// FileMaker's code for success.
+func (r *ResponseData) IsSuccess() bool {
+	message := r.FirstMessage()
+	return message != nil && message.Code == "0"
+}
+
+// FirstMessage returns the first message of the response, or nil when it has
+// none.
+func (r *ResponseData) FirstMessage() *Message {
+	if r == nil || len(r.Messages) == 0 {
+		return nil
+	}
+	return &r.Messages[0]
+}
+
+// TotalRecordCount returns how many records the layout's table holds,
+// regardless of the found set. It is zero when the server sent no dataInfo.
+func (r *ResponseData) TotalRecordCount() int {
+	if r == nil {
+		return 0
+	}
+	return int(r.Response.DataInfo.TotalRecordCount)
+}
+
 type Message struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
@@ -16,6 +79,28 @@ type Response struct {
 	Token    string   `json:"token,omitempty"`
 	DataInfo DataInfo `json:"dataInfo,omitempty"`
 	Data     []Datum  `json:"data,omitempty"`
+
+	ScriptResult           string `json:"scriptResult,omitempty"`
+	ScriptError            string `json:"scriptError,omitempty"`
+	ScriptResultPreRequest string `json:"scriptResult.prerequest,omitempty"`
+	ScriptErrorPreRequest  string `json:"scriptError.prerequest,omitempty"`
+	ScriptResultPreSort    string `json:"scriptResult.presort,omitempty"`
+	ScriptErrorPreSort     string `json:"scriptError.presort,omitempty"`
+
+	Layouts []LayoutInfo `json:"layouts,omitempty"`
+	Scripts []ScriptInfo `json:"scripts,omitempty"`
+}
+
+// scriptErr returns a ScriptStageError for the first script run before the
+// operation (pre-request, then pre-sort) that reported a non-zero error.
+func (r *Response) scriptErr() error {
+	if r.ScriptErrorPreRequest != "" && r.ScriptErrorPreRequest != "0" {
+		return &ScriptStageError{Stage: PreRequestStage, Code: r.ScriptErrorPreRequest, Result: r.ScriptResultPreRequest}
+	}
+	if r.ScriptErrorPreSort != "" && r.ScriptErrorPreSort != "0" {
+		return &ScriptStageError{Stage: PreSortStage, Code: r.ScriptErrorPreSort, Result: r.ScriptResultPreSort}
+	}
+	return nil
 }
 
 type Datum struct {
@@ -25,6 +110,159 @@ type Datum struct {
 	ModID      string      `json:"modId,omitempty"`
 }
 
+// RecordIDInt returns the record ID as an integer, e.g. to compare or sort
+// records numerically.
+func (d *Datum) RecordIDInt() (int64, error) {
+	return strconv.ParseInt(d.RecordID, 10, 64)
+}
+
+// ModIDInt returns the modification ID as an integer.
+func (d *Datum) ModIDInt() (int64, error) {
+	return strconv.ParseInt(d.ModID, 10, 64)
+}
+
+// Int64 returns the value of field parsed as an integer. Number values are
+// decoded as json.Number, so integers above 2^53 keep their precision.
+func (d *Datum) Int64(field string) (int64, error) {
+	value, err := d.field(field)
+	if err != nil {
+		return 0, err
+	}
+	switch v := value.(type) {
+	case json.Number:
+		return v.Int64()
+	case string:
+		return strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+	case float64:
+		if v != math.Trunc(v) {
+			return 0, fmt.Errorf("filemaker: field %s is not an integer: %v", field, v)
+		}
+		return int64(v), nil
+	default:
+		return 0, fmt.Errorf("filemaker: field %s is not a number: %T", field, value)
+	}
+}
+
+// Float64 returns the value of field parsed as a float.
+func (d *Datum) Float64(field string) (float64, error) {
+	value, err := d.field(field)
+	if err != nil {
+		return 0, err
+	}
+	switch v := value.(type) {
+	case json.Number:
+		return v.Float64()
+	case string:
+		return strconv.ParseFloat(strings.TrimSpace(v), 64)
+	case float64:
+		return v, nil
+	default:
+		return 0, fmt.Errorf("filemaker: field %s is not a number: %T", field, value)
+	}
+}
+
+var (
+	defaultTruthyValues = []string{"1", "true", "yes", "on"}
+	defaultFalsyValues  = []string{"", "0", "false", "no", "off"}
+)
+
+// Bool returns the value of a FileMaker boolean or checkbox field. "1",
+// "true", "yes" and "on" are true and "", "0", "false", "no" and "off" are
+// false, case-insensitively; any other value is an error.
+func (d *Datum) Bool(field string) (bool, error) {
+	value, err := d.field(field)
+	if err != nil {
+		return false, err
+	}
+	text := ""
+	if value != nil {
+		text = strings.TrimSpace(fmt.Sprint(value))
+	}
+	if containsFold(defaultTruthyValues, text) {
+		return true, nil
+	}
+	if containsFold(defaultFalsyValues, text) {
+		return false, nil
+	}
+	return false, fmt.Errorf("filemaker: field %s is not a boolean: %q", field, text)
+}
+
+// BoolWith returns true when the value of field is one of truthy,
+// case-insensitively, and false otherwise. It suits solutions storing
+// booleans in another language, e.g. BoolWith("activo", "sí", "si").
+func (d *Datum) BoolWith(field string, truthy ...string) (bool, error) {
+	value, err := d.field(field)
+	if err != nil {
+		return false, err
+	}
+	if value == nil {
+		return false, nil
+	}
+	return containsFold(truthy, strings.TrimSpace(fmt.Sprint(value))), nil
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *Datum) field(name string) (interface{}, error) {
+	fieldData, err := fieldDataMap(d.FieldData)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := fieldData[name]
+	if !ok {
+		return nil, fmt.Errorf("filemaker: field %s not found", name)
+	}
+	return value, nil
+}
+
+// PortalRow is a related record returned in the portalData of a record.
+// Fields holds the portal fields without the recordId and modId keys.
+type PortalRow struct {
+	RecordID string
+	ModID    string
+	Fields   map[string]interface{}
+}
+
+// Portal returns the rows of the portal name, or nil when the record has no
+// such portal.
+func (d *Datum) Portal(name string) []PortalRow {
+	portals, ok := d.PortalData.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	rows, ok := portals[name].([]interface{})
+	if !ok {
+		return nil
+	}
+	portalRows := make([]PortalRow, 0, len(rows))
+	for _, row := range rows {
+		fields, ok := row.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		portalRow := PortalRow{Fields: make(map[string]interface{}, len(fields))}
+		for key, value := range fields {
+			switch key {
+			case "recordId":
+				portalRow.RecordID = fmt.Sprint(value)
+			case "modId":
+				portalRow.ModID = fmt.Sprint(value)
+			default:
+				portalRow.Fields[key] = value
+			}
+		}
+		portalRows = append(portalRows, portalRow)
+	}
+	return portalRows
+}
+
 type DataInfo struct {
 	Database         string `json:"database,omitempty"`
 	Layout           string `json:"layout,omitempty"`
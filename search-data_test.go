@@ -0,0 +1,152 @@
+package filemaker
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestDatum_Int64(t *testing.T) {
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"response":{"data":[{"fieldData":{"id":123456789012345678,"price":10.5,"code":"42","name":"pablo"},"recordId":"1","modId":"0"}]},"messages":[{"code":"0","message":"OK"}]}`))
+	})
+	resp, err := NewRecordService("test", "test_layout", newTestClient(t, server.URL)).GetById("1")
+	if err != nil {
+		t.Fatalf("GetById() error = %v", err)
+	}
+	datum := resp.Response.Data[0]
+
+	t.Run("Test large integer", func(t *testing.T) {
+		got, err := datum.Int64("id")
+		if err != nil || got != 123456789012345678 {
+			t.Errorf("Int64() was incorrect, got: %d, %v, want: %d", got, err, int64(123456789012345678))
+		}
+	})
+
+	t.Run("Test numeric string", func(t *testing.T) {
+		got, err := datum.Int64("code")
+		if err != nil || got != 42 {
+			t.Errorf("Int64() was incorrect, got: %d, %v, want: %d", got, err, 42)
+		}
+	})
+
+	t.Run("Test float", func(t *testing.T) {
+		got, err := datum.Float64("price")
+		if err != nil || got != 10.5 {
+			t.Errorf("Float64() was incorrect, got: %v, %v, want: %v", got, err, 10.5)
+		}
+		if _, err := datum.Int64("price"); err == nil {
+			t.Errorf("Int64() of a decimal should fail")
+		}
+	})
+
+	t.Run("Test invalid fields", func(t *testing.T) {
+		if _, err := datum.Int64("name"); err == nil {
+			t.Errorf("Int64() of a text should fail")
+		}
+		if _, err := datum.Float64("missing"); err == nil {
+			t.Errorf("Float64() of a missing field should fail")
+		}
+	})
+}
+
+func TestDatum_Bool(t *testing.T) {
+	datum := &Datum{FieldData: map[string]interface{}{
+		"one": "1", "zero": json.Number("0"), "yes": "Yes", "no": "NO", "on": "on",
+		"empty": "", "null": nil, "other": "maybe", "spanish": "Sí",
+	}}
+
+	tests := []struct {
+		field   string
+		want    bool
+		wantErr bool
+	}{
+		{field: "one", want: true},
+		{field: "zero", want: false},
+		{field: "yes", want: true},
+		{field: "no", want: false},
+		{field: "on", want: true},
+		{field: "empty", want: false},
+		{field: "null", want: false},
+		{field: "other", wantErr: true},
+		{field: "missing", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			got, err := datum.Bool(tt.field)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("Bool() was incorrect, got: %v, %v, want: %v, wantErr %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("custom truthy values", func(t *testing.T) {
+		got, err := datum.BoolWith("spanish", "sí", "si")
+		if err != nil || !got {
+			t.Errorf("BoolWith() was incorrect, got: %v, %v, want: true", got, err)
+		}
+		if got, _ := datum.BoolWith("one", "sí"); got {
+			t.Errorf("BoolWith() was incorrect, got: true, want: false")
+		}
+	})
+}
+
+func TestDatum_Portal(t *testing.T) {
+	var resp ResponseData
+	err := json.Unmarshal([]byte(`{"response":{"data":[{"fieldData":{"id":"1"},"portalData":{"lines":[{"recordId":"10","lines::qty":2,"modId":"3"},{"recordId":"11","lines::qty":5,"modId":"0"}]},"recordId":"1","modId":"0"}]}}`), &resp)
+	if err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	datum := resp.Response.Data[0]
+
+	rows := datum.Portal("lines")
+	if len(rows) != 2 {
+		t.Fatalf("Rows was incorrect, got: %d, want: %d", len(rows), 2)
+	}
+	if rows[0].RecordID != "10" || rows[0].ModID != "3" || len(rows[0].Fields) != 1 || rows[0].Fields["lines::qty"] != float64(2) {
+		t.Errorf("Row was incorrect, got: %+v", rows[0])
+	}
+	if datum.Portal("missing") != nil {
+		t.Errorf("Missing portal should return nil")
+	}
+}
+
+func TestResponseData_IsSuccess(t *testing.T) {
+	tests := []struct {
+		name     string
+		response *ResponseData
+		want     bool
+		wantCode string
+	}{
+		{name: "success", response: &ResponseData{Messages: []Message{{Code: "0", Message: "OK"}}}, want: true, wantCode: "0"},
+		{name: "error", response: &ResponseData{Messages: []Message{{Code: "401", Message: "No records match the request"}}}, wantCode: "401"},
+		{name: "no messages", response: &ResponseData{}},
+		{name: "nil", response: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.response.IsSuccess(); got != tt.want {
+				t.Errorf("IsSuccess() was incorrect, got: %v, want: %v", got, tt.want)
+			}
+			message := tt.response.FirstMessage()
+			if (message == nil) != (tt.wantCode == "") || (message != nil && message.Code != tt.wantCode) {
+				t.Errorf("FirstMessage() was incorrect, got: %v, want code: %q", message, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestDatum_RecordIDInt(t *testing.T) {
+	datum := &Datum{RecordID: "10", ModID: "9"}
+	recordId, err := datum.RecordIDInt()
+	if err != nil || recordId != 10 {
+		t.Errorf("RecordIDInt() was incorrect, got: %d, %v, want: %d", recordId, err, 10)
+	}
+	modId, err := datum.ModIDInt()
+	if err != nil || modId != 9 {
+		t.Errorf("ModIDInt() was incorrect, got: %d, %v, want: %d", modId, err, 9)
+	}
+	if _, err := (&Datum{RecordID: "ORD-1"}).RecordIDInt(); err == nil {
+		t.Errorf("RecordIDInt() of a non-numeric ID should fail")
+	}
+}
@@ -1,21 +1,39 @@
 package filemaker
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
 type SearchService interface {
 	Do() (interface{}, error)
 }
 
-const findQueryPath = "fmi/data/%s/databases/%s/layouts/%s/_find"
-
 type searchService struct {
 	client    *Client
 	database  string
 	layout    string
 	seachData *searchData
+	rawQuery  json.RawMessage
+	// pagingErr holds an invalid offset or limit until Do reports it.
+	pagingErr error
+	// tiebreaker is the field OrderByStable sorts on last.
+	tiebreaker string
+	params     url.Values
+	// excludeFields overrides the client's response field blocklist when
+	// not nil.
+	excludeFields []string
+	// modifiedField and modifiedValue hold the ModifiedSince restriction,
+	// applied again to the query groups set after it.
+	modifiedField string
+	modifiedValue string
 }
 
 func NewSearchService(database, layout string, client *Client) *searchService {
@@ -34,6 +52,60 @@ type searchData struct {
 	Limit      string              `json:"limit,omitempty"`
 	Offset     string              `json:"offset,omitempty"`
 	Sort       []*Sorter           `json:"sort,omitempty"`
+
+	ResponseLayout string     `json:"layout.response,omitempty"`
+	Portal         []string   `json:"portal,omitempty"`
+	DateFormat     DateFormat `json:"dateformats,omitempty"`
+
+	Script                string `json:"script,omitempty"`
+	ScriptParam           string `json:"script.param,omitempty"`
+	ScriptPreRequest      string `json:"script.prerequest,omitempty"`
+	ScriptPreRequestParam string `json:"script.prerequest.param,omitempty"`
+	ScriptPreSort         string `json:"script.presort,omitempty"`
+	ScriptPreSortParam    string `json:"script.presort.param,omitempty"`
+}
+
+// Clone returns a deep copy of the search, so variants can be derived from a
+// base search, also concurrently, without affecting it.
+func (s *searchService) Clone() *searchService {
+	searchData := *s.seachData
+	searchData.QueryGroup = make([]map[string]string, 0, len(s.seachData.QueryGroup))
+	for _, queryMap := range s.seachData.QueryGroup {
+		queryMapCopy := make(map[string]string, len(queryMap))
+		for name, value := range queryMap {
+			queryMapCopy[name] = value
+		}
+		searchData.QueryGroup = append(searchData.QueryGroup, queryMapCopy)
+	}
+	if s.seachData.Sort != nil {
+		searchData.Sort = make([]*Sorter, len(s.seachData.Sort))
+		for i, sorter := range s.seachData.Sort {
+			if sorter != nil {
+				sorterCopy := *sorter
+				searchData.Sort[i] = &sorterCopy
+			}
+		}
+	}
+	if s.seachData.Portal != nil {
+		searchData.Portal = append([]string(nil), s.seachData.Portal...)
+	}
+	clone := &searchService{
+		client:     s.client,
+		database:   s.database,
+		layout:     s.layout,
+		seachData:  &searchData,
+		rawQuery:   append(json.RawMessage(nil), s.rawQuery...),
+		pagingErr:  s.pagingErr,
+		tiebreaker: s.tiebreaker,
+		params:     copyParams(s.params),
+
+		modifiedField: s.modifiedField,
+		modifiedValue: s.modifiedValue,
+	}
+	if s.excludeFields != nil {
+		clone.excludeFields = append([]string{}, s.excludeFields...)
+	}
+	return clone
 }
 
 func (s *searchService) GroupQueries(queryGroups ...*groupQuery) *searchService {
@@ -47,39 +119,587 @@ func (s *searchService) GroupQueries(queryGroups ...*groupQuery) *searchService
 		queries = append(queries, queryMap)
 	}
 	s.seachData.QueryGroup = queries
+	if s.modifiedField != "" {
+		s.restrictModified()
+	}
+	return s
+}
+
+// ExcludeFields removes names from the fieldData of the records found, in
+// place of the client's SetResponseFieldBlocklist. Calling it without names
+// keeps every field.
+func (s *searchService) ExcludeFields(names ...string) *searchService {
+	s.excludeFields = append([]string{}, names...)
+	return s
+}
+
+// SetParam adds a query parameter the search doesn't model, e.g. one of a
+// newer Data API version. It never overrides a parameter set by the search,
+// and an unknown parameter can make the server fail the request.
+func (s *searchService) SetParam(key, value string) *searchService {
+	if s.params == nil {
+		s.params = url.Values{}
+	}
+	s.params.Set(key, value)
 	return s
 }
 
+// WhereGroup sets the find requests of the search to the ones built by q.
+func (s *searchService) WhereGroup(q *QueryBuilder) *searchService {
+	return s.GroupQueries(q.Groups()...)
+}
+
+// SetOffset sets the 1-based position of the first record returned: "1" is
+// the first record, not "0". An empty offset leaves it to the server. An
+// invalid offset makes Do fail with a *ValidationError.
 func (s *searchService) SetOffset(offset string) *searchService {
+	if offset != "" {
+		value, err := pagingValue("offset", offset)
+		if err != nil {
+			s.pagingErr = err
+			return s
+		}
+		return s.SetOffsetInt(value)
+	}
 	s.seachData.Offset = offset
 	return s
 }
 
+// SetLimit sets the maximum number of records returned, at least 1. An
+// empty limit leaves it to the server. An invalid limit makes Do fail with a
+// *ValidationError.
 func (s *searchService) SetLimit(limit string) *searchService {
+	if limit != "" {
+		value, err := pagingValue("limit", limit)
+		if err != nil {
+			s.pagingErr = err
+			return s
+		}
+		return s.SetLimitInt(value)
+	}
 	s.seachData.Limit = limit
 	return s
 }
+
+// SetOffsetInt sets the 1-based position of the first record returned.
+func (s *searchService) SetOffsetInt(offset int) *searchService {
+	if err := validateOffset(offset); err != nil {
+		s.pagingErr = err
+		return s
+	}
+	s.seachData.Offset = strconv.Itoa(offset)
+	return s
+}
+
+// SetLimitInt sets the maximum number of records returned, at least 1.
+func (s *searchService) SetLimitInt(limit int) *searchService {
+	if err := validateLimit(limit); err != nil {
+		s.pagingErr = err
+		return s
+	}
+	s.seachData.Limit = strconv.Itoa(limit)
+	return s
+}
+
+func pagingValue(field, value string) (int, error) {
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, &ValidationError{Field: field, Message: fmt.Sprintf("%q is not an integer", value)}
+	}
+	return parsed, nil
+}
+
+func validateOffset(offset int) error {
+	if offset < 1 {
+		return &ValidationError{Field: "offset", Message: fmt.Sprintf("%d is lower than 1, offsets are 1-based", offset)}
+	}
+	return nil
+}
+
+func validateLimit(limit int) error {
+	if limit < 1 {
+		return &ValidationError{Field: "limit", Message: fmt.Sprintf("%d is lower than 1", limit)}
+	}
+	return nil
+}
+
+// validatePaging checks the offset and limit strings of a request, an empty
+// one being left to the server.
+func validatePaging(offset, limit string) error {
+	if offset != "" {
+		value, err := pagingValue("offset", offset)
+		if err != nil {
+			return err
+		}
+		if err := validateOffset(value); err != nil {
+			return err
+		}
+	}
+	if limit != "" {
+		value, err := pagingValue("limit", limit)
+		if err != nil {
+			return err
+		}
+		return validateLimit(value)
+	}
+	return nil
+}
+
 func (s *searchService) Sorters(sorters ...*Sorter) *searchService {
 	s.seachData.Sort = sorters
 	return s
 }
 
+// SetSortTiebreaker sets the field OrderByStable sorts on last, instead of
+// DefaultSortTiebreaker. It must hold a unique value per record.
+func (s *searchService) SetSortTiebreaker(field string) *searchService {
+	s.tiebreaker = field
+	return s
+}
+
+// OrderByStable adds a sort on field and keeps an ascending sort on the
+// tiebreaker field last, so records with equal values come back in the same
+// order on every page. Call it once per sort field, most significant first.
+func (s *searchService) OrderByStable(field string, order SortOrder) *searchService {
+	tiebreaker := s.tiebreaker
+	if tiebreaker == "" {
+		tiebreaker = DefaultSortTiebreaker
+	}
+	sorters := make([]*Sorter, 0, len(s.seachData.Sort)+2)
+	for _, sorter := range s.seachData.Sort {
+		if sorter == nil || sorter.FieldName != tiebreaker {
+			sorters = append(sorters, sorter)
+		}
+	}
+	sorters = append(sorters, NewSorter(field, order))
+	if field != tiebreaker {
+		sorters = append(sorters, NewSorter(tiebreaker, Ascending))
+	}
+	s.seachData.Sort = sorters
+	return s
+}
+
+// ModifiedSince restricts every query group to records whose timestamp field
+// is at or after since, and sends the find with format so the server parses
+// the timestamp the way it was formatted. The restriction also applies to
+// the query groups set afterwards with GroupQueries or WhereGroup.
+func (s *searchService) ModifiedSince(field string, since time.Time, format DateFormat) *searchService {
+	s.modifiedField = field
+	s.modifiedValue = NewQueryFieldOperator(field, FormatTimestamp(since, format), GreaterThanEqual).valueWithOp()
+	s.seachData.DateFormat = format
+	s.restrictModified()
+	return s
+}
+
+func (s *searchService) restrictModified() {
+	if len(s.seachData.QueryGroup) == 0 {
+		s.seachData.QueryGroup = append(s.seachData.QueryGroup, make(map[string]string))
+	}
+	for _, queryMap := range s.seachData.QueryGroup {
+		queryMap[s.modifiedField] = s.modifiedValue
+	}
+}
+
+// SetResponseLayout sets the layout used to build the response data, which
+// can differ from the layout the find is performed on.
+func (s *searchService) SetResponseLayout(layout string) *searchService {
+	s.seachData.ResponseLayout = layout
+	return s
+}
+
+// SetPortals restricts the portals returned to names.
+func (s *searchService) SetPortals(names ...string) *searchService {
+	s.seachData.Portal = names
+	return s
+}
+
+func (s *searchService) SetDateFormat(dateFormat DateFormat) *searchService {
+	s.seachData.DateFormat = dateFormat
+	return s
+}
+
+// SetScript runs script after the find and the sort are performed.
+func (s *searchService) SetScript(script, param string) *searchService {
+	s.seachData.Script = script
+	s.seachData.ScriptParam = param
+	return s
+}
+
+// SetPreRequestScript runs script before the find is performed.
+func (s *searchService) SetPreRequestScript(script, param string) *searchService {
+	s.seachData.ScriptPreRequest = script
+	s.seachData.ScriptPreRequestParam = param
+	return s
+}
+
+// SetPreSortScript runs script after the find but before the found set is sorted.
+func (s *searchService) SetPreSortScript(script, param string) *searchService {
+	s.seachData.ScriptPreSort = script
+	s.seachData.ScriptPreSortParam = param
+	return s
+}
+
+// SetScripts sets every script of scriptContext on the find request. Scripts
+// left empty in scriptContext keep their current value.
+func (s *searchService) SetScripts(scriptContext *ScriptContext) *searchService {
+	if scriptContext == nil {
+		return s
+	}
+	if scriptContext.Script != "" {
+		s.SetScript(scriptContext.Script, scriptContext.ScriptParam)
+	}
+	if scriptContext.PreRequest != "" {
+		s.SetPreRequestScript(scriptContext.PreRequest, scriptContext.PreRequestParam)
+	}
+	if scriptContext.PreSort != "" {
+		s.SetPreSortScript(scriptContext.PreSort, scriptContext.PreSortParam)
+	}
+	return s
+}
+
+// FirstWhere returns the first record whose field equals value, or
+// ErrNotFound. The value is matched literally, so find operators such as "*"
+// or "@" in it are not live. Sorters and scripts of the search are kept, its
+// queries and paging are not.
+func (s *searchService) FirstWhere(field, value string) (*Datum, error) {
+	data, err := s.where(field, value, "1")
+	if err != nil {
+		return nil, err
+	}
+	return &data[0], nil
+}
+
+// SingleWhere is like FirstWhere but returns ErrMultipleRecords when more
+// than one record matches.
+func (s *searchService) SingleWhere(field, value string) (*Datum, error) {
+	data, err := s.where(field, value, "2")
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > 1 {
+		return nil, ErrMultipleRecords
+	}
+	return &data[0], nil
+}
+
+// Count returns how many records match the search, fetching a single record
+// to read the found count, so the offset and limit of the search are
+// ignored. A search matching nothing counts 0.
+func (s *searchService) Count() (int, error) {
+	search := s.Clone()
+	search.pagingErr = nil
+	resp, err := search.SetOffset("").SetLimitInt(1).Do()
+	if err != nil {
+		return 0, err
+	}
+	if err := resp.Err(); err != nil {
+		if IsNoRecordsError(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return int(resp.Response.DataInfo.FoundCount), nil
+}
+
+func (s *searchService) where(field, value, limit string) ([]Datum, error) {
+	search := s.Clone()
+	search.rawQuery = nil
+	search.pagingErr = nil
+	search.GroupQueries(NewGroupQuery(NewQueryFieldOperator(field, value, EqualLiteral))).
+		SetOffset("").
+		SetLimit(limit)
+
+	resp, err := search.Do()
+	if err != nil {
+		return nil, err
+	}
+	if err := resp.Err(); err != nil {
+		if IsNoRecordsError(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if len(resp.Response.Data) == 0 {
+		return nil, ErrNotFound
+	}
+	return resp.Response.Data, nil
+}
+
+// SetRawQuery sends query as the find body instead of the one built by the
+// search, for Data API parameters the package doesn't model yet. The raw body
+// bypasses every validation and is not checked for correctness; session and
+// path handling are unchanged. FirstWhere and SingleWhere ignore it, and the
+// operations paging through the found set (Stream, UpdateAll) reject it.
+func (s *searchService) SetRawQuery(query json.RawMessage) *searchService {
+	s.rawQuery = query
+	return s
+}
+
 func (s *searchService) Do() (*ResponseData, error) {
+	if s.rawQuery == nil {
+		if err := s.validate(); err != nil {
+			return nil, err
+		}
+	}
 
-	responseAuth, err := s.client.Connect(s.database)
+	body, err := s.body()
 	if err != nil {
 		return nil, err
 	}
-	defer s.client.Disconnect(s.database, responseAuth.Response.Token)
+	key := http.MethodPost + " " + s.path() + "?" + s.params.Encode() + " " + string(body) + " " + s.excludeKey()
+	return s.client.coalesce(key, func() (*ResponseData, error) {
+		ctx := s.client.operationContext(context.Background())
+		responseAuth, err := s.client.connect(ctx, s.database)
+		if err != nil {
+			return nil, err
+		}
+		defer s.client.disconnect(ctx, s.database, responseAuth.Response.Token)
+
+		return s.find(ctx, responseAuth.Response.Token)
+	})
+}
+
+// excludeKey tells apart, for request coalescing, searches using the client's
+// field blocklist from searches overriding it.
+func (s *searchService) excludeKey() string {
+	if s.excludeFields == nil {
+		return ""
+	}
+	return "exclude:" + strings.Join(s.excludeFields, ",")
+}
+
+// validate catches the misconfigurations the server would reject with an
+// unclear error: a find needs at least one non-empty query group, valid
+// paging, sorters and portal names.
+func (s *searchService) validate() error {
+	if s.pagingErr != nil {
+		return s.pagingErr
+	}
+	if len(s.seachData.QueryGroup) == 0 {
+		return &ValidationError{Field: "query", Message: "at least one query group is required, use List to get all records"}
+	}
+	for i, queryMap := range s.seachData.QueryGroup {
+		if len(queryMap) == 0 {
+			return &ValidationError{Field: "query", Message: fmt.Sprintf("query group %d is empty", i)}
+		}
+	}
+	for _, name := range s.seachData.Portal {
+		if name == "" {
+			return &ValidationError{Field: "portal", Message: "empty portal name"}
+		}
+	}
+	return validateSorters(s.seachData.Sort)
+}
+
+func (s *searchService) path() string {
+	return s.client.dataPath("databases", s.database, "layouts", s.layout, "_find")
+}
 
-	path := fmt.Sprintf(findQueryPath, s.client.version, s.database, s.layout)
+// body returns the serialized find request.
+func (s *searchService) body() (json.RawMessage, error) {
+	if s.rawQuery != nil {
+		return s.rawQuery, nil
+	}
+	return json.Marshal(s.seachData)
+}
+
+func (s *searchService) find(ctx context.Context, token string) (*ResponseData, error) {
+	body, err := s.body()
+	if err != nil {
+		return nil, err
+	}
 
 	options := &performRequestOptions{
-		Method:  http.MethodPost,
-		Path:    path,
-		Body:    s.seachData,
-		Headers: http.Header{"Authorization": []string{fmt.Sprintf("Bearer %s", responseAuth.Response.Token)}},
+		Method:        http.MethodPost,
+		Path:          s.path(),
+		Params:        withExtraParams(nil, s.params),
+		Body:          body,
+		Headers:       authorizationHeader(token),
+		excludeFields: s.excludeFields,
+	}
+
+	return s.client.executeQuery(ctx, options)
+}
+
+// UpdateAll edits every record matching the search with fields, under a
+// single session, running up to concurrency edits at a time. It returns how
+// many records were updated; edits that fail are reported in a *BatchError
+// keyed by record ID.
+func (s *searchService) UpdateAll(fields map[string]interface{}, concurrency int) (int, error) {
+	return s.UpdateAllAdaptive(fields, NewAdaptiveConcurrency(concurrency, concurrency))
+}
+
+// UpdateAllAdaptive is like UpdateAll but lets concurrency tune how many
+// edits run at a time from their latency and failures.
+func (s *searchService) UpdateAllAdaptive(fields map[string]interface{}, concurrency *AdaptiveConcurrency) (int, error) {
+	if len(fields) == 0 {
+		return 0, &ValidationError{Field: "fieldData", Message: "no fields to update"}
+	}
+	if concurrency == nil {
+		return 0, &ValidationError{Field: "concurrency", Message: "no concurrency controller"}
+	}
+	if s.rawQuery != nil {
+		return 0, errRawQueryPaging()
+	}
+	if err := s.validate(); err != nil {
+		return 0, err
+	}
+
+	ctx := s.client.operationContext(context.Background())
+	responseAuth, err := s.client.connect(ctx, s.database)
+	if err != nil {
+		return 0, err
+	}
+	defer s.client.disconnect(ctx, s.database, responseAuth.Response.Token)
+
+	token := responseAuth.Response.Token
+	var recordIds []string
+	err = s.findPages(ctx, token, findPageSize, func(data []Datum) error {
+		for _, datum := range data {
+			recordIds = append(recordIds, datum.RecordID)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	records := NewRecordService(s.database, s.layout, s.client)
+	payload := &Payload{FieldData: fields}
+	batchErr := &BatchError{Errors: make(map[string]error)}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	ids := make(chan string)
+	for i := 0; i < int(concurrency.max); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for recordId := range ids {
+				concurrency.acquire()
+				start := time.Now()
+				resp, err := records.edit(ctx, token, recordId, payload)
+				if err == nil {
+					err = resp.Err()
+				}
+				concurrency.release(time.Since(start), err != nil)
+				if err != nil {
+					mu.Lock()
+					batchErr.Errors[recordId] = err
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, recordId := range recordIds {
+		ids <- recordId
+	}
+	close(ids)
+	wg.Wait()
+
+	updated := len(recordIds) - len(batchErr.Errors)
+	if len(batchErr.Errors) > 0 {
+		return updated, batchErr
 	}
+	return updated, nil
+}
+
+const findPageSize = 100
+
+// Stream runs the find under a single session and sends its records one by
+// one, fetching pageSize records at a time. Both channels are closed once
+// the found set is exhausted; at most one error is sent. Call stop when done
+// with the records, as with the cancel of context.WithCancel: a receiver
+// that stops reading early must call it so the session is closed.
+//
+//	records, errs, stop := search.Stream(ctx, 500)
+//	defer stop()
+//	for datum := range records {
+//		...
+//	}
+//	if err := <-errs; err != nil {
+//		...
+//	}
+func (s *searchService) Stream(ctx context.Context, pageSize int) (records <-chan *Datum, errs <-chan error, stop context.CancelFunc) {
+	ctx, stop = context.WithCancel(ctx)
+	recordsCh := make(chan *Datum)
+	errsCh := make(chan error, 1)
+	search := s.Clone()
+	go func() {
+		defer close(errsCh)
+		defer close(recordsCh)
+		if err := search.stream(ctx, pageSize, recordsCh); err != nil {
+			errsCh <- err
+		}
+	}()
+	return recordsCh, errsCh, stop
+}
+
+func (s *searchService) stream(ctx context.Context, pageSize int, records chan<- *Datum) error {
+	if pageSize < 1 {
+		return &ValidationError{Field: "limit", Message: fmt.Sprintf("page size %d must be at least 1", pageSize)}
+	}
+	if s.rawQuery != nil {
+		return errRawQueryPaging()
+	}
+	if err := s.validate(); err != nil {
+		return err
+	}
+
+	ctx = s.client.operationContext(ctx)
+	responseAuth, err := s.client.connect(ctx, s.database)
+	if err != nil {
+		return err
+	}
+	defer s.client.disconnect(ctx, s.database, responseAuth.Response.Token)
 
-	return s.client.executeQuery(options)
+	return s.findPages(ctx, responseAuth.Response.Token, pageSize, func(data []Datum) error {
+		for i := range data {
+			select {
+			case records <- &data[i]:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+}
+
+// errRawQueryPaging is returned by the operations paging through a find: the
+// offset and limit of a raw query can't be changed, so every page would be
+// the same.
+func errRawQueryPaging() error {
+	return &ValidationError{Field: "query", Message: "a raw query can't be paged"}
+}
+
+// findPages runs the find page by page under token, calling fn with the
+// records of each page until the found set is exhausted. A find matching no
+// records is not an error.
+func (s *searchService) findPages(ctx context.Context, token string, pageSize int, fn func(data []Datum) error) error {
+	if s.rawQuery != nil {
+		return errRawQueryPaging()
+	}
+	search := s.Clone()
+	search.SetLimitInt(pageSize)
+	for offset := 1; ; offset += pageSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		search.SetOffsetInt(offset)
+		resp, err := search.find(ctx, token)
+		if err != nil {
+			return err
+		}
+		if err := resp.Err(); err != nil {
+			if IsNoRecordsError(err) {
+				return nil
+			}
+			return err
+		}
+		if err := fn(resp.Response.Data); err != nil {
+			return err
+		}
+		if len(resp.Response.Data) < pageSize {
+			return nil
+		}
+	}
 }
@@ -1,8 +1,18 @@
 package filemaker
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func Test_searchService_GroupQuery(t *testing.T) {
@@ -33,4 +43,656 @@ func Test_searchService_GroupQuery(t *testing.T) {
 			t.Errorf("JSON was incorrect, got: %s, want: %s", string(b), "[{\"nombre\":\"==pablo\"}]")
 		}
 	})
+	t.Run("Test query group with related fields", func(t *testing.T) {
+		search := NewSearchService("test", "test_layout", nil)
+		search.GroupQueries(
+			NewGroupQuery(
+				NewQueryFieldOperator("Orders::Status", "open", Equal),
+				NewQueryFieldOperator("Customers::Name", "pablo", BeginsWith),
+			),
+			NewGroupQuery(
+				NewQueryFieldOperator("Orders::Total", "100", Equal),
+			),
+		)
+		b, _ := json.Marshal(search.seachData.QueryGroup)
+		want := "[{\"Customers::Name\":\"==pablo*\",\"Orders::Status\":\"==open\"},{\"Orders::Total\":\"==100\"}]"
+		if string(b) != want {
+			t.Errorf("JSON was incorrect, got: %s, want: %s", string(b), want)
+		}
+	})
+
+	t.Run("Test related field in find request body", func(t *testing.T) {
+		var body string
+		server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+			data, _ := ioutil.ReadAll(r.Body)
+			body = string(data)
+			w.Write([]byte(`{"response":{"data":[]},"messages":[{"code":"0","message":"OK"}]}`))
+		})
+		_, err := NewSearchService("test", "test_layout", newTestClient(t, server.URL)).
+			WhereGroup(Q().And(NewQueryFieldOperator("Orders::Status", "open", Equal))).
+			Sorters(NewSorter("Orders::Date", Descending)).
+			Do()
+		if err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+		want := `{"query":[{"Orders::Status":"==open"}],"sort":[{"fieldName":"Orders::Date","sortOrder":"descend"}]}`
+		if body != want {
+			t.Errorf("Body was incorrect, got: %s, want: %s", body, want)
+		}
+	})
+}
+
+func Test_searchService_SetScripts(t *testing.T) {
+	t.Run("Test scripts in find body", func(t *testing.T) {
+		search := NewSearchService("test", "test_layout", nil)
+		search.GroupQueries(
+			NewGroupQuery(
+				NewQueryFieldOperator("nombre", "pablo", Equal),
+			),
+		).SetScripts(
+			NewScriptContext().
+				WithPreRequest("Validate", "strict").
+				WithPreSort("Prepare", "").
+				WithScript("Finish", "done"),
+		)
+		b, _ := json.Marshal(search.seachData)
+		want := "{\"query\":[{\"nombre\":\"==pablo\"}],\"script\":\"Finish\",\"script.param\":\"done\",\"script.prerequest\":\"Validate\",\"script.prerequest.param\":\"strict\",\"script.presort\":\"Prepare\"}"
+		if string(b) != want {
+			t.Errorf("JSON was incorrect, got: %s, want: %s", string(b), want)
+		}
+	})
+
+	t.Run("Test nil scripts", func(t *testing.T) {
+		search := NewSearchService("test", "test_layout", nil)
+		search.SetScript("Finish", "").SetScripts(nil)
+		if search.seachData.Script != "Finish" {
+			t.Errorf("Script was incorrect, got: %s, want: %s", search.seachData.Script, "Finish")
+		}
+	})
+}
+
+func Test_searchService_FirstWhere(t *testing.T) {
+	newSearch := func(t *testing.T, response string) *searchService {
+		server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			if !strings.Contains(string(body), `"query":[{"code":"==A-1"}]`) {
+				t.Errorf("Find body was incorrect, got: %s", string(body))
+			}
+			w.Write([]byte(response))
+		})
+		return NewSearchService("test", "test_layout", newTestClient(t, server.URL))
+	}
+	one := `{"response":{"data":[{"fieldData":{"code":"A-1"},"recordId":"1","modId":"0"}]},"messages":[{"code":"0","message":"OK"}]}`
+	two := `{"response":{"data":[{"fieldData":{"code":"A-1"},"recordId":"1","modId":"0"},{"fieldData":{"code":"A-1"},"recordId":"2","modId":"0"}]},"messages":[{"code":"0","message":"OK"}]}`
+	none := `{"response":{},"messages":[{"code":"401","message":"No records match the request"}]}`
+
+	t.Run("Test first record", func(t *testing.T) {
+		datum, err := newSearch(t, two).FirstWhere("code", "A-1")
+		if err != nil || datum.RecordID != "1" {
+			t.Errorf("FirstWhere() was incorrect, got: %v, %v, want: recordId %s", datum, err, "1")
+		}
+	})
+
+	t.Run("Test no records", func(t *testing.T) {
+		if _, err := newSearch(t, none).FirstWhere("code", "A-1"); err != ErrNotFound {
+			t.Errorf("FirstWhere() error was incorrect, got: %v, want: %v", err, ErrNotFound)
+		}
+	})
+
+	t.Run("Test single record", func(t *testing.T) {
+		datum, err := newSearch(t, one).SingleWhere("code", "A-1")
+		if err != nil || datum.RecordID != "1" {
+			t.Errorf("SingleWhere() was incorrect, got: %v, %v, want: recordId %s", datum, err, "1")
+		}
+	})
+
+	t.Run("Test multiple records", func(t *testing.T) {
+		if _, err := newSearch(t, two).SingleWhere("code", "A-1"); err != ErrMultipleRecords {
+			t.Errorf("SingleWhere() error was incorrect, got: %v, want: %v", err, ErrMultipleRecords)
+		}
+	})
+
+	t.Run("Test literal value and earlier paging error", func(t *testing.T) {
+		var body string
+		server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+			data, _ := ioutil.ReadAll(r.Body)
+			body = string(data)
+			w.Write([]byte(one))
+		})
+		search := NewSearchService("test", "test_layout", newTestClient(t, server.URL)).SetLimit("-1")
+		if _, err := search.FirstWhere("email", "a*@x.com"); err != nil {
+			t.Fatalf("FirstWhere() error = %v", err)
+		}
+		if !strings.Contains(body, `"email":"==a\\*\\@x.com"`) {
+			t.Errorf("Find body was incorrect, got: %s, want the operators escaped", body)
+		}
+	})
+}
+
+func TestNewSearchServiceFor(t *testing.T) {
+	config := NewLayoutConfig("test", "test_layout").
+		ResponseLayout("compact").
+		DateFormat(ISO8601DateFormat).
+		Portals("lines")
+
+	t.Run("Test layout defaults", func(t *testing.T) {
+		search := NewSearchServiceFor(nil, config)
+		b, _ := json.Marshal(search.seachData)
+		want := "{\"query\":[],\"layout.response\":\"compact\",\"portal\":[\"lines\"],\"dateformats\":2}"
+		if string(b) != want {
+			t.Errorf("JSON was incorrect, got: %s, want: %s", string(b), want)
+		}
+	})
+
+	t.Run("Test overrides win", func(t *testing.T) {
+		search := NewSearchServiceFor(nil, config).SetResponseLayout("full").SetDateFormat(USDateFormat)
+		b, _ := json.Marshal(search.seachData)
+		want := "{\"query\":[],\"layout.response\":\"full\",\"portal\":[\"lines\"]}"
+		if string(b) != want {
+			t.Errorf("JSON was incorrect, got: %s, want: %s", string(b), want)
+		}
+	})
+}
+
+func Test_searchService_ModifiedSince(t *testing.T) {
+	since := time.Date(2020, time.March, 5, 14, 30, 0, 0, time.UTC)
+
+	t.Run("Test ISO timestamp on every group", func(t *testing.T) {
+		search := NewSearchService("test", "test_layout", nil)
+		search.GroupQueries(
+			NewGroupQuery(NewQueryFieldOperator("status", "open", Equal)),
+			NewGroupQuery(NewQueryFieldOperator("status", "late", Equal)),
+		).ModifiedSince("modified", since, ISO8601DateFormat)
+		b, _ := json.Marshal(search.seachData)
+		want := "{\"query\":[{\"modified\":\"\\u003e=2020-03-05T14:30:00\",\"status\":\"==open\"},{\"modified\":\"\\u003e=2020-03-05T14:30:00\",\"status\":\"==late\"}],\"dateformats\":2}"
+		if string(b) != want {
+			t.Errorf("JSON was incorrect, got: %s, want: %s", string(b), want)
+		}
+	})
+
+	t.Run("Test US timestamp without groups", func(t *testing.T) {
+		search := NewSearchService("test", "test_layout", nil).ModifiedSince("modified", since, USDateFormat)
+		b, _ := json.Marshal(search.seachData.QueryGroup)
+		want := "[{\"modified\":\"\\u003e=03/05/2020 14:30:00\"}]"
+		if string(b) != want {
+			t.Errorf("JSON was incorrect, got: %s, want: %s", string(b), want)
+		}
+	})
+
+	t.Run("Test groups set afterwards", func(t *testing.T) {
+		search := NewSearchService("test", "test_layout", nil).
+			ModifiedSince("modified", since, ISO8601DateFormat).
+			WhereGroup(Q().
+				Or(NewQueryFieldOperator("status", "open", Equal)).
+				Or(NewQueryFieldOperator("status", "late", Equal)))
+		b, _ := json.Marshal(search.Clone().seachData.QueryGroup)
+		want := "[{\"modified\":\"\\u003e=2020-03-05T14:30:00\",\"status\":\"==open\"},{\"modified\":\"\\u003e=2020-03-05T14:30:00\",\"status\":\"==late\"}]"
+		if string(b) != want {
+			t.Errorf("JSON was incorrect, got: %s, want: %s", string(b), want)
+		}
+	})
+}
+
+func Test_searchService_Clone(t *testing.T) {
+	base := NewSearchService("test", "test_layout", nil).
+		GroupQueries(NewGroupQuery(NewQueryFieldOperator("status", "open", Equal))).
+		Sorters(NewSorter("name", Ascending)).
+		SetLimit("10")
+	want, _ := json.Marshal(base.seachData)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			clone := base.Clone().SetLimit(strconv.Itoa(i)).ModifiedSince("modified", time.Now(), ISO8601DateFormat)
+			clone.seachData.Sort[0].SortOrder = Descending
+		}(i)
+	}
+	wg.Wait()
+
+	got, _ := json.Marshal(base.seachData)
+	if string(got) != string(want) {
+		t.Errorf("Base search was modified, got: %s, want: %s", string(got), string(want))
+	}
+}
+
+func Test_searchService_SetRawQuery(t *testing.T) {
+	raw := `{"query":[{"name":"pablo"}],"future.option":true}`
+	var body string
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		body = string(data)
+		w.Write([]byte(`{"response":{"data":[]},"messages":[{"code":"0","message":"OK"}]}`))
+	})
+	search := NewSearchService("test", "test_layout", newTestClient(t, server.URL)).
+		Sorters(NewSorter("name", "")).
+		SetRawQuery(json.RawMessage(raw))
+
+	if _, err := search.Do(); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if body != raw {
+		t.Errorf("Find body was incorrect, got: %s, want: %s", body, raw)
+	}
+}
+
+func Test_searchService_RawQueryPaging(t *testing.T) {
+	var finds int
+	var body string
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		finds++
+		data, _ := ioutil.ReadAll(r.Body)
+		body = string(data)
+		records := make([]string, 0, findPageSize)
+		for i := 1; i <= findPageSize; i++ {
+			records = append(records, fmt.Sprintf(`{"fieldData":{},"recordId":"%d","modId":"0"}`, i))
+		}
+		fmt.Fprintf(w, `{"response":{"data":[%s]},"messages":[{"code":"0","message":"OK"}]}`, strings.Join(records, ","))
+	})
+	search := NewSearchService("test", "test_layout", newTestClient(t, server.URL)).
+		SetRawQuery(json.RawMessage(`{"query":[{"status":"open"}]}`))
+
+	t.Run("Test UpdateAll", func(t *testing.T) {
+		finds = 0
+		_, err := search.UpdateAll(map[string]interface{}{"status": "closed"}, 1)
+		if _, ok := err.(*ValidationError); !ok || finds != 0 {
+			t.Errorf("UpdateAll() was incorrect, got: %v after %d finds, want: %T before any find", err, finds, &ValidationError{})
+		}
+	})
+
+	t.Run("Test Stream", func(t *testing.T) {
+		finds = 0
+		records, errs, stop := search.Stream(context.Background(), findPageSize)
+		defer stop()
+		for range records {
+		}
+		if _, ok := (<-errs).(*ValidationError); !ok || finds != 0 {
+			t.Errorf("Stream() was incorrect after %d finds, want: %T before any find", finds, &ValidationError{})
+		}
+	})
+
+	t.Run("Test FirstWhere ignores the raw query", func(t *testing.T) {
+		if _, err := search.FirstWhere("id", "7"); err != nil {
+			t.Fatalf("FirstWhere() error = %v", err)
+		}
+		if !strings.Contains(body, `"id":"==7"`) || !strings.Contains(body, `"limit":"1"`) {
+			t.Errorf("Find body was incorrect, got: %s", body)
+		}
+	})
+}
+
+func Test_searchService_UpdateAll(t *testing.T) {
+	var mu sync.Mutex
+	edits := make(map[string]string)
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/_find"):
+			w.Write([]byte(`{"response":{"data":[{"fieldData":{},"recordId":"1","modId":"0"},{"fieldData":{},"recordId":"2","modId":"0"},{"fieldData":{},"recordId":"3","modId":"0"}]},"messages":[{"code":"0","message":"OK"}]}`))
+		case r.Method == http.MethodPatch:
+			body, _ := ioutil.ReadAll(r.Body)
+			recordId := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+			mu.Lock()
+			edits[recordId] = string(body)
+			mu.Unlock()
+			if recordId == "3" {
+				w.Write([]byte(`{"response":{},"messages":[{"code":"301","message":"Record is in use by another user"}]}`))
+				return
+			}
+			w.Write([]byte(`{"response":{"modId":"1"},"messages":[{"code":"0","message":"OK"}]}`))
+		}
+	})
+	search := NewSearchService("test", "test_layout", newTestClient(t, server.URL)).
+		GroupQueries(NewGroupQuery(NewQueryFieldOperator("status", "open", Equal)))
+
+	updated, err := search.UpdateAll(map[string]interface{}{"status": "closed"}, 2)
+	if batchErr, ok := err.(*BatchError); !ok || batchErr.Errors["3"] == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %T for record 3", err, &BatchError{})
+	}
+	if updated != 2 || len(edits) != 3 || edits["1"] != "{\"fieldData\":{\"status\":\"closed\"}}" {
+		t.Errorf("UpdateAll was incorrect, got: %d updated, edits %v", updated, edits)
+	}
+	if server.disconnectCount() != 1 {
+		t.Errorf("Disconnect calls was incorrect, got: %d, want: %d", server.disconnectCount(), 1)
+	}
+
+	t.Run("Test invalid search opens no session", func(t *testing.T) {
+		var requests int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+		}))
+		t.Cleanup(server.Close)
+		search := NewSearchService("test", "test_layout", newTestClient(t, server.URL))
+		_, err := search.UpdateAll(map[string]interface{}{"status": "closed"}, 2)
+		if _, ok := err.(*ValidationError); !ok || requests != 0 {
+			t.Errorf("UpdateAll() was incorrect, got: %v after %d requests, want: %T before any request", err, requests, &ValidationError{})
+		}
+	})
+}
+
+func Test_searchService_Paging(t *testing.T) {
+	base := func() *searchService {
+		return NewSearchService("test", "test_layout", nil).GroupQueries(NewGroupQuery(NewQueryFieldOperator("id", "1", Equal)))
+	}
+	tests := []struct {
+		name    string
+		search  *searchService
+		want    string
+		wantErr bool
+	}{
+		{name: "int paging", search: base().SetOffsetInt(11).SetLimitInt(10), want: "{\"query\":[{\"id\":\"==1\"}],\"limit\":\"10\",\"offset\":\"11\"}"},
+		{name: "string paging", search: base().SetOffset("1").SetLimit("5"), want: "{\"query\":[{\"id\":\"==1\"}],\"limit\":\"5\",\"offset\":\"1\"}"},
+		{name: "zero offset", search: base().SetOffsetInt(0), wantErr: true},
+		{name: "negative limit", search: base().SetLimit("-1"), wantErr: true},
+		{name: "zero limit", search: base().SetLimitInt(0), wantErr: true},
+		{name: "not a number", search: base().SetOffset("first"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.search.validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if _, err := tt.search.Do(); err == nil {
+					t.Errorf("Do() with invalid paging should fail")
+				}
+				return
+			}
+			b, _ := json.Marshal(tt.search.seachData)
+			if string(b) != tt.want {
+				t.Errorf("JSON was incorrect, got: %s, want: %s", string(b), tt.want)
+			}
+		})
+	}
+}
+
+func Test_searchService_GroupQuerySameField(t *testing.T) {
+	t.Run("Test last query on a field wins", func(t *testing.T) {
+		search := NewSearchService("test", "test_layout", nil)
+		search.GroupQueries(
+			NewGroupQuery(
+				NewQueryFieldOperator("age", "18", GreaterThanEqual),
+				NewQueryFieldOperator("name", "pablo", Equal),
+			).AddQuery(NewQueryFieldOperator("age", "65", LessThan)),
+		)
+		b, _ := json.Marshal(search.seachData.QueryGroup)
+		want := "[{\"age\":\"\\u003c65\",\"name\":\"==pablo\"}]"
+		if string(b) != want {
+			t.Errorf("JSON was incorrect, got: %s, want: %s", string(b), want)
+		}
+	})
+
+	t.Run("Test same field in separate groups", func(t *testing.T) {
+		search := NewSearchService("test", "test_layout", nil)
+		search.GroupQueries(
+			NewGroupQuery(NewQueryFieldOperator("age", "18", LessThan)),
+			NewGroupQuery(NewQueryFieldOperator("age", "65", GreaterThan)),
+		)
+		b, _ := json.Marshal(search.seachData.QueryGroup)
+		want := "[{\"age\":\"\\u003c18\"},{\"age\":\"\\u003e65\"}]"
+		if string(b) != want {
+			t.Errorf("JSON was incorrect, got: %s, want: %s", string(b), want)
+		}
+	})
+}
+
+func Test_searchService_Count(t *testing.T) {
+	newSearch := func(t *testing.T, response string) *searchService {
+		server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			if !strings.Contains(string(body), `"limit":"1"`) {
+				t.Errorf("Find body was incorrect, got: %s", string(body))
+			}
+			w.Write([]byte(response))
+		})
+		return NewSearchService("test", "test_layout", newTestClient(t, server.URL)).
+			GroupQueries(NewGroupQuery(NewQueryFieldOperator("status", "open", Equal))).
+			SetLimit("50")
+	}
+
+	t.Run("Test matching records", func(t *testing.T) {
+		count, err := newSearch(t, `{"response":{"dataInfo":{"foundCount":1234,"returnedCount":1},"data":[{"fieldData":{},"recordId":"1","modId":"0"}]},"messages":[{"code":"0","message":"OK"}]}`).Count()
+		if err != nil || count != 1234 {
+			t.Errorf("Count() was incorrect, got: %d, %v, want: %d", count, err, 1234)
+		}
+	})
+
+	t.Run("Test no records", func(t *testing.T) {
+		count, err := newSearch(t, `{"response":{},"messages":[{"code":"401","message":"No records match the request"}]}`).Count()
+		if err != nil || count != 0 {
+			t.Errorf("Count() was incorrect, got: %d, %v, want: %d", count, err, 0)
+		}
+	})
+
+	t.Run("Test invalid paging is ignored", func(t *testing.T) {
+		search := newSearch(t, `{"response":{"dataInfo":{"foundCount":7,"returnedCount":1},"data":[{"fieldData":{},"recordId":"1","modId":"0"}]},"messages":[{"code":"0","message":"OK"}]}`).SetOffset("0")
+		count, err := search.Count()
+		if err != nil || count != 7 {
+			t.Errorf("Count() was incorrect, got: %d, %v, want: %d", count, err, 7)
+		}
+	})
+}
+
+func Test_searchService_OrderByStable(t *testing.T) {
+	sortOf := func(s *searchService) string {
+		data, _ := json.Marshal(s.seachData.Sort)
+		return string(data)
+	}
+
+	t.Run("Test default tiebreaker", func(t *testing.T) {
+		got := sortOf(NewSearchService("test", "test_layout", nil).OrderByStable("status", Descending).OrderByStable("name", Ascending))
+		want := `[{"fieldName":"status","sortOrder":"descend"},{"fieldName":"name","sortOrder":"ascend"},{"fieldName":"PrimaryKey","sortOrder":"ascend"}]`
+		if got != want {
+			t.Errorf("Sort was incorrect, got: %s, want: %s", got, want)
+		}
+	})
+
+	t.Run("Test custom tiebreaker", func(t *testing.T) {
+		got := sortOf(NewSearchService("test", "test_layout", nil).SetSortTiebreaker("id").OrderByStable("name", Ascending))
+		want := `[{"fieldName":"name","sortOrder":"ascend"},{"fieldName":"id","sortOrder":"ascend"}]`
+		if got != want {
+			t.Errorf("Sort was incorrect, got: %s, want: %s", got, want)
+		}
+	})
+
+	t.Run("Test sort on tiebreaker", func(t *testing.T) {
+		got := sortOf(NewSearchService("test", "test_layout", nil).SetSortTiebreaker("id").OrderByStable("id", Descending))
+		want := `[{"fieldName":"id","sortOrder":"descend"}]`
+		if got != want {
+			t.Errorf("Sort was incorrect, got: %s, want: %s", got, want)
+		}
+	})
+}
+
+func Test_searchService_SetParam(t *testing.T) {
+	var rawQuery string
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		rawQuery = r.URL.RawQuery
+		w.Write([]byte(`{"response":{"data":[]},"messages":[{"code":"0","message":"OK"}]}`))
+	})
+	search := NewSearchService("test", "test_layout", newTestClient(t, server.URL)).
+		GroupQueries(NewGroupQuery(NewQueryFieldOperator("status", "open", Equal)))
+
+	if _, err := search.Clone().SetParam("_future", "on").Do(); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if rawQuery != "_future=on" {
+		t.Errorf("Query was incorrect, got: %s, want: %s", rawQuery, "_future=on")
+	}
+	if _, err := search.Do(); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if rawQuery != "" {
+		t.Errorf("Query of the original search was incorrect, got: %s, want none", rawQuery)
+	}
+}
+
+func Test_searchService_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		search  *searchService
+		wantErr bool
+	}{
+		{name: "valid", search: NewSearchService("test", "test_layout", nil).GroupQueries(NewGroupQuery(NewQueryFieldOperator("id", "1", Equal))).SetPortals("LineItems")},
+		{name: "no query group", search: NewSearchService("test", "test_layout", nil), wantErr: true},
+		{name: "empty query group", search: NewSearchService("test", "test_layout", nil).GroupQueries(NewGroupQuery()), wantErr: true},
+		{name: "empty portal name", search: NewSearchService("test", "test_layout", nil).GroupQueries(NewGroupQuery(NewQueryFieldOperator("id", "1", Equal))).SetPortals(""), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.search.validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if _, ok := err.(*ValidationError); err != nil && !ok {
+				t.Errorf("validate() error type was incorrect, got: %T, want: %T", err, &ValidationError{})
+			}
+		})
+	}
+}
+
+func Test_searchService_Stream(t *testing.T) {
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Offset string `json:"offset"`
+			Limit  string `json:"limit"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		offset, _ := strconv.Atoi(body.Offset)
+		limit, _ := strconv.Atoi(body.Limit)
+		var data []string
+		for id := offset; id < offset+limit && id <= 5; id++ {
+			data = append(data, fmt.Sprintf(`{"fieldData":{},"recordId":"%d","modId":"0"}`, id))
+		}
+		if len(data) == 0 {
+			w.Write([]byte(`{"response":{},"messages":[{"code":"401","message":"No records match the request"}]}`))
+			return
+		}
+		fmt.Fprintf(w, `{"response":{"data":[%s]},"messages":[{"code":"0","message":"OK"}]}`, strings.Join(data, ","))
+	})
+	search := NewSearchService("test", "test_layout", newTestClient(t, server.URL)).
+		GroupQueries(NewGroupQuery(NewQueryFieldOperator("status", "open", Equal)))
+
+	t.Run("Test all records", func(t *testing.T) {
+		records, errs, stop := search.Stream(context.Background(), 2)
+		defer stop()
+		var ids []string
+		for datum := range records {
+			ids = append(ids, datum.RecordID)
+		}
+		if err := <-errs; err != nil {
+			t.Fatalf("Stream() error = %v", err)
+		}
+		if strings.Join(ids, ",") != "1,2,3,4,5" {
+			t.Errorf("Stream() was incorrect, got: %v, want: %v", ids, "1,2,3,4,5")
+		}
+	})
+
+	t.Run("Test receiver cancels", func(t *testing.T) {
+		disconnects := server.disconnectCount()
+		ctx, cancel := context.WithCancel(context.Background())
+		records, errs, stop := search.Stream(ctx, 2)
+		defer stop()
+		<-records
+		cancel()
+		for range records {
+		}
+		if err := <-errs; !errors.Is(err, context.Canceled) {
+			t.Errorf("Stream() error was incorrect, got: %v, want: %v", err, context.Canceled)
+		}
+		if server.disconnectCount() != disconnects+1 {
+			t.Errorf("Disconnect calls was incorrect, got: %d, want: %d", server.disconnectCount(), disconnects+1)
+		}
+	})
+
+	t.Run("Test receiver stops early", func(t *testing.T) {
+		disconnects := server.disconnectCount()
+		records, errs, stop := search.Stream(context.Background(), 2)
+		<-records
+		stop()
+		if err := <-errs; !errors.Is(err, context.Canceled) {
+			t.Errorf("Stream() error was incorrect, got: %v, want: %v", err, context.Canceled)
+		}
+		if server.disconnectCount() != disconnects+1 {
+			t.Errorf("Disconnect calls was incorrect, got: %d, want: %d", server.disconnectCount(), disconnects+1)
+		}
+	})
+
+	t.Run("Test invalid page size", func(t *testing.T) {
+		records, errs, stop := search.Stream(context.Background(), 0)
+		defer stop()
+		for range records {
+		}
+		if _, ok := (<-errs).(*ValidationError); !ok {
+			t.Errorf("Stream() error type was incorrect, want: %T", &ValidationError{})
+		}
+	})
+}
+
+func Test_searchService_ExcludeFields(t *testing.T) {
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"response":{"data":[{"fieldData":{"name":"a","notes":"long","image":"x"},"recordId":"1","modId":"0"}]},"messages":[{"code":"0","message":"OK"}]}`))
+	})
+	client := newTestClient(t, server.URL, SetResponseFieldBlocklist([]string{"notes"}))
+	search := NewSearchService("test", "test_layout", client).
+		GroupQueries(NewGroupQuery(NewQueryFieldOperator("name", "a", Equal)))
+	fieldsOf := func(t *testing.T, s *searchService) string {
+		resp, err := s.Do()
+		if err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+		data, _ := json.Marshal(resp.Response.Data[0].FieldData)
+		return string(data)
+	}
+
+	t.Run("Test client blocklist", func(t *testing.T) {
+		if got := fieldsOf(t, search.Clone()); got != `{"image":"x","name":"a"}` {
+			t.Errorf("FieldData was incorrect, got: %s, want: %s", got, `{"image":"x","name":"a"}`)
+		}
+	})
+
+	t.Run("Test search override", func(t *testing.T) {
+		if got := fieldsOf(t, search.Clone().ExcludeFields("image")); got != `{"name":"a","notes":"long"}` {
+			t.Errorf("FieldData was incorrect, got: %s, want: %s", got, `{"name":"a","notes":"long"}`)
+		}
+	})
+
+	t.Run("Test search keeps every field", func(t *testing.T) {
+		if got := fieldsOf(t, search.Clone().ExcludeFields()); got != `{"image":"x","name":"a","notes":"long"}` {
+			t.Errorf("FieldData was incorrect, got: %s, want: %s", got, `{"image":"x","name":"a","notes":"long"}`)
+		}
+	})
+}
+
+func Test_searchService_UpdateAllAdaptive(t *testing.T) {
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/_find"):
+			w.Write([]byte(`{"response":{"data":[{"fieldData":{},"recordId":"1","modId":"0"},{"fieldData":{},"recordId":"2","modId":"0"}]},"messages":[{"code":"0","message":"OK"}]}`))
+		case r.Method == http.MethodPatch:
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"response":{},"messages":[{"code":"301","message":"Record is in use by another user"}]}`))
+		}
+	})
+	search := NewSearchService("test", "test_layout", newTestClient(t, server.URL)).
+		GroupQueries(NewGroupQuery(NewQueryFieldOperator("status", "open", Equal)))
+	concurrency := NewAdaptiveConcurrency(1, 8)
+	concurrency.limit = 8
+
+	updated, err := search.UpdateAllAdaptive(map[string]interface{}{"status": "closed"}, concurrency)
+	if _, ok := err.(*BatchError); !ok || updated != 0 {
+		t.Errorf("UpdateAllAdaptive() was incorrect, got: %d updated, %v", updated, err)
+	}
+	if concurrency.Limit() >= 8 {
+		t.Errorf("Limit() was incorrect, got: %d, want it lowered by the FileMaker errors", concurrency.Limit())
+	}
+
+	t.Run("Test nil controller", func(t *testing.T) {
+		disconnects := server.disconnectCount()
+		_, err := search.UpdateAllAdaptive(map[string]interface{}{"status": "closed"}, nil)
+		if _, ok := err.(*ValidationError); !ok || server.disconnectCount() != disconnects {
+			t.Errorf("UpdateAllAdaptive() error was incorrect, got: %v, want: %T before any session", err, &ValidationError{})
+		}
+	})
 }
@@ -1,5 +1,7 @@
 package filemaker
 
+import "fmt"
+
 type SortOrder string
 
 const (
@@ -7,6 +9,11 @@ const (
 	Descending SortOrder = "descend"
 )
 
+// DefaultSortTiebreaker is the field searchService.OrderByStable sorts on
+// last. The Data API can't sort on the internal record ID, so it is the
+// unique primary key field FileMaker adds to new tables.
+const DefaultSortTiebreaker = "PrimaryKey"
+
 type Sorter struct {
 	FieldName string    `json:"fieldName"`
 	SortOrder SortOrder `json:"sortOrder"`
@@ -19,6 +26,31 @@ func NewSorter(fieldName string, sortOrder SortOrder) *Sorter {
 	}
 }
 
+// Validate checks that the sorter has a field name and a known sort order.
+func (s *Sorter) Validate() error {
+	if s.FieldName == "" {
+		return &ValidationError{Field: "sort", Message: "empty field name"}
+	}
+	switch s.SortOrder {
+	case Ascending, Descending:
+		return nil
+	default:
+		return &ValidationError{Field: "sort", Message: fmt.Sprintf("invalid sort order %q for field %s", s.SortOrder, s.FieldName)}
+	}
+}
+
+func validateSorters(sorters []*Sorter) error {
+	for _, sorter := range sorters {
+		if sorter == nil {
+			return &ValidationError{Field: "sort", Message: "nil sorter"}
+		}
+		if err := sorter.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (so *SortOrder) String() string {
 	return string(*so)
 }
@@ -0,0 +1,35 @@
+package filemaker
+
+import "testing"
+
+func TestSorter_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		sorter  *Sorter
+		wantErr bool
+	}{
+		{name: "ascend", sorter: NewSorter("name", Ascending)},
+		{name: "descend", sorter: NewSorter("name", Descending)},
+		{name: "empty order", sorter: NewSorter("name", ""), wantErr: true},
+		{name: "unknown order", sorter: NewSorter("name", "up"), wantErr: true},
+		{name: "empty field", sorter: NewSorter("", Ascending), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.sorter.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if _, ok := err.(*ValidationError); err != nil && !ok {
+				t.Errorf("Validate() error type was incorrect, got: %T, want: %T", err, &ValidationError{})
+			}
+		})
+	}
+
+	t.Run("List rejects invalid sorters before connecting", func(t *testing.T) {
+		service := NewRecordService("test", "test_layout", nil)
+		if _, err := service.List("1", "10", NewSorter("name", "")); err == nil {
+			t.Errorf("List() with an invalid sorter should fail")
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package filemaker
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"log"
+	"net/http"
+)
+
+// errNoPinnedCertificate fails the TLS handshake when the server chain holds
+// none of the certificates set with SetPinnedCertificates.
+var errNoPinnedCertificate = errors.New("filemaker: no pinned certificate matches the server certificate chain")
+
+// configureTLS applies the TLS options to a copy of the http client, so a
+// shared client such as http.DefaultClient is never modified. A Doer set with
+// SetDoer bypasses the http client, so the options are rejected rather than
+// silently ignored.
+func (c *Client) configureTLS() error {
+	if c.minTLSVersion == 0 && len(c.pinnedCertificates) == 0 && !c.insecureSkipVerify {
+		return nil
+	}
+	if c.doer != nil {
+		return errors.New("TLS options can't be combined with SetDoer")
+	}
+
+	var transport *http.Transport
+	switch t := c.httpClient.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		return errors.New("TLS options require an *http.Transport")
+	}
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	if c.minTLSVersion != 0 {
+		transport.TLSClientConfig.MinVersion = c.minTLSVersion
+	}
+	if len(c.pinnedCertificates) > 0 {
+		transport.TLSClientConfig.VerifyPeerCertificate = verifyPinned(c.pinnedCertificates)
+	}
+	if c.insecureSkipVerify {
+		transport.TLSClientConfig.InsecureSkipVerify = true
+		c.warnf("filemaker: TLS certificate verification is disabled for %s, never use InsecureSkipTLSVerify in production", c.url)
+	}
+
+	httpClient := *c.httpClient
+	httpClient.Transport = transport
+	c.httpClient = &httpClient
+	c.ownedTransport = transport
+	return nil
+}
+
+func verifyPinned(pinned []*x509.Certificate) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		for _, raw := range rawCerts {
+			for _, cert := range pinned {
+				if bytes.Equal(raw, cert.Raw) {
+					return nil
+				}
+			}
+		}
+		return errNoPinnedCertificate
+	}
+}
+
+// warnf writes a warning to the client logger, or to the standard logger when
+// none is set.
+func (c *Client) warnf(format string, args ...interface{}) {
+	if c.logger != nil {
+		c.logger.Printf(format, args...)
+		return
+	}
+	log.Printf(format, args...)
+}
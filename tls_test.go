@@ -0,0 +1,82 @@
+package filemaker
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_TLSOptions(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"response":{},"messages":[{"code":"0","message":"OK"}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	t.Run("Test pinned certificate matches", func(t *testing.T) {
+		client := newTestClient(t, server.URL,
+			SetHttpClient(server.Client()),
+			SetMinTLSVersion(tls.VersionTLS12),
+			SetPinnedCertificates([]*x509.Certificate{server.Certificate()}))
+		if err := client.Ping(context.Background()); err != nil {
+			t.Errorf("Ping() error = %v", err)
+		}
+	})
+
+	t.Run("Test pinned certificate mismatch", func(t *testing.T) {
+		client := newTestClient(t, server.URL,
+			SetHttpClient(server.Client()),
+			SetPinnedCertificates([]*x509.Certificate{{Raw: []byte("other certificate")}}))
+		if err := client.Ping(context.Background()); !errors.Is(err, errNoPinnedCertificate) {
+			t.Errorf("Ping() error was incorrect, got: %v, want: %v", err, errNoPinnedCertificate)
+		}
+	})
+
+	t.Run("Test shared client is not modified", func(t *testing.T) {
+		shared := server.Client()
+		transport := shared.Transport
+		newTestClient(t, server.URL, SetHttpClient(shared), SetMinTLSVersion(tls.VersionTLS13))
+		if shared.Transport != transport || transport.(*http.Transport).TLSClientConfig.MinVersion == tls.VersionTLS13 {
+			t.Errorf("SetMinTLSVersion() modified the shared http client")
+		}
+	})
+
+	t.Run("Test combined with a Doer", func(t *testing.T) {
+		doer := doerFunc(func(req *http.Request) (*http.Response, error) { return nil, errors.New("unused") })
+		if _, err := NewClient(SetURL(server.URL), SetDoer(doer), SetMinTLSVersion(tls.VersionTLS12)); err == nil {
+			t.Errorf("NewClient() with SetMinTLSVersion and SetDoer should fail")
+		}
+		if _, err := NewClient(SetURL(server.URL), SetDoer(doer), SetPinnedCertificates([]*x509.Certificate{server.Certificate()})); err == nil {
+			t.Errorf("NewClient() with SetPinnedCertificates and SetDoer should fail")
+		}
+	})
+}
+
+func TestClient_InsecureSkipTLSVerify(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"response":{},"messages":[{"code":"0","message":"OK"}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	var buf bytes.Buffer
+	client := newTestClient(t, server.URL, InsecureSkipTLSVerify(), SetLogger(log.New(&buf, "", 0)))
+	if err := client.Ping(context.Background()); err != nil {
+		t.Errorf("Ping() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "TLS certificate verification is disabled") {
+		t.Errorf("Warning was incorrect, got: %q", buf.String())
+	}
+
+	t.Run("Test combined with a Doer", func(t *testing.T) {
+		doer := doerFunc(func(req *http.Request) (*http.Response, error) { return nil, errors.New("unused") })
+		if _, err := NewClient(SetURL(server.URL), SetDoer(doer), InsecureSkipTLSVerify()); err == nil {
+			t.Errorf("NewClient() with InsecureSkipTLSVerify and SetDoer should fail")
+		}
+	})
+}
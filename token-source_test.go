@@ -0,0 +1,39 @@
+package filemaker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_SetTokenSource(t *testing.T) {
+	var sessionCalls int
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/sessions") {
+			sessionCalls++
+		}
+		authorization = r.Header.Get("Authorization")
+		w.Write([]byte(`{"response":{"data":[]},"messages":[{"code":"0","message":"OK"}]}`))
+	}))
+	t.Cleanup(server.Close)
+	client := newTestClient(t, server.URL, SetTokenSource(StaticToken("external-token")))
+
+	if _, err := NewRecordService("test", "test_layout", client).GetById("1"); err != nil {
+		t.Fatalf("GetById() error = %v", err)
+	}
+	if authorization != "Bearer external-token" {
+		t.Errorf("Authorization was incorrect, got: %s, want: %s", authorization, "Bearer external-token")
+	}
+	if sessionCalls != 0 {
+		t.Errorf("Session calls were incorrect, got: %d, want: %d", sessionCalls, 0)
+	}
+}
+
+func TestStaticToken(t *testing.T) {
+	if _, err := StaticToken("").Token(context.Background()); err == nil {
+		t.Errorf("Token() of an empty static token should fail")
+	}
+}